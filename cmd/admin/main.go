@@ -0,0 +1,85 @@
+package main
+
+import (
+	"api/database"
+	"api/env"
+	"api/modules/todos"
+	"api/modules/users/cli"
+	"api/modules/users/infra/hashpassword"
+	"api/modules/users/infra/repositories"
+	"api/modules/users/usecases"
+	"fmt"
+	"os"
+)
+
+// admin runs the account management commands against the database, every
+// command writes json on stdout, e.g.: admin user list
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	// env
+	env, err := env.NewEnvironment()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return cli.ExitServerError
+	}
+
+	// database
+	db, err := database.MakeConnection(
+		env.Database.User,
+		env.Database.Host,
+		env.Database.Port,
+		env.Database.Password,
+		env.Database.Dbname,
+		env.Database.Sslmode,
+	)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return cli.ExitServerError
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return cli.ExitServerError
+	}
+
+	// token revocation
+	tokenRevocationRepository := repositories.NewTokenRevocationRepository(db)
+	refreshTokenRepository := repositories.NewRefreshTokenRepository(db)
+	tokenRevocation := usecases.NewTokenRevocation(tokenRevocationRepository, refreshTokenRepository)
+
+	// users
+	userRepository := repositories.NewUserRepository(db)
+	roleRepository := repositories.NewRoleRepository(db)
+	hashPassword := hashpassword.NewHashPassword()
+	userUsecase := usecases.NewUserUsecase(userRepository, hashPassword)
+	roleUsecase := usecases.NewRoleUsecase(roleRepository, userRepository)
+
+	// todos
+	todoRepository := todos.NewTodoRepository(db)
+	boardRepository := todos.NewBoardRepository(db)
+	todoUsecase := todos.NewTodoUsecase(todoRepository, userUsecase, boardRepository)
+
+	userController := cli.NewUserController(userUsecase, roleUsecase, tokenRevocation)
+	todoController := cli.NewTodoController(todoUsecase)
+	tokenController := cli.NewTokenController(tokenRevocation)
+	migrateController := cli.NewMigrateController(migrator)
+
+	router := cli.NewRouter()
+	router.Handle("user", "create", userController.CreateUser())
+	router.Handle("user", "list", userController.GetAllUser())
+	router.Handle("user", "disable", userController.DisableUser())
+	router.Handle("user", "set-role", userController.SetRoleUser())
+	router.Handle("user", "reset-password", userController.ResetPasswordUser())
+	router.Handle("status", "list", todoController.GetAllStatusTodo())
+	router.Handle("todo", "export", todoController.ExportTodos())
+	router.Handle("migrate", "up", migrateController.MigrateUp())
+	router.Handle("migrate", "down", migrateController.MigrateDown())
+	router.Handle("token", "revoke", tokenController.RevokeUserTokens())
+
+	return router.Run(args)
+}
@@ -22,6 +22,5 @@ func MakeConnection(user, host, port, password, dbname, sslmode string) (*sql.DB
 		return nil, err
 	}
 
-	fmt.Println("[ * ] DB base connected")
 	return db, nil
 }
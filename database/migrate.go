@@ -0,0 +1,237 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationLockKey is the pg advisory lock held while migrating, so
+// instances started together don't apply the same migration twice
+const migrationLockKey int64 = 7265382910
+
+var (
+	ErrMigrationFileName = errors.New("migration file should be named like 0001_name.up.sql or 0001_name.down.sql")
+)
+
+// Migration is a numbered pair of embedded sql files
+type Migration struct {
+	Version int64  `json:"version"`
+	Name    string `json:"name"`
+	Up      string `json:"-"`
+	Down    string `json:"-"`
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+}
+
+// NewMigrator loads the migrations embedded from database/migrations
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db, migrations}, nil
+}
+
+func loadMigrations(fileSystem fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fileSystem, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrationsByVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrMigrationFileName, fileName)
+		}
+
+		nameSplited := strings.SplitN(strings.TrimSuffix(fileName, "."+direction+".sql"), "_", 2)
+		if len(nameSplited) != 2 {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationFileName, fileName)
+		}
+		version, err := strconv.ParseInt(nameSplited[0], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationFileName, fileName)
+		}
+
+		content, err := fs.ReadFile(fileSystem, path.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := migrationsByVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: nameSplited[1]}
+			migrationsByVersion[version] = migration
+		}
+		if migration.Name != nameSplited[1] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, nameSplited[1])
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(migrationsByVersion))
+	for _, migration := range migrationsByVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d needs the up and the down files", migration.Version)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up applies every pending migration, each one in its own transaction
+func (migrator *Migrator) Up() (applied []*Migration, err error) {
+	applied = make([]*Migration, 0)
+	err = migrator.withLock(func(conn *sql.Conn) error {
+		versions, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrator.migrations {
+			if versions[migration.Version] {
+				continue
+			}
+			sqlInsert := `
+				INSERT INTO public.schema_migrations (version, name, applied_at)
+				VALUES ($1, $2, $3);
+			`
+			err := execInTx(conn, migration.Up, sqlInsert, migration.Version, migration.Name, time.Now().UTC())
+			if err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return
+}
+
+// Down reverts the last applied migrations, steps is how many
+func (migrator *Migrator) Down(steps int) (reverted []*Migration, err error) {
+	reverted = make([]*Migration, 0)
+	err = migrator.withLock(func(conn *sql.Conn) error {
+		versions, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(migrator.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := migrator.migrations[i]
+			if !versions[migration.Version] {
+				continue
+			}
+			sqlDelete := `
+				DELETE FROM public.schema_migrations
+				WHERE version=$1;
+			`
+			err := execInTx(conn, migration.Down, sqlDelete, migration.Version)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return
+}
+
+// withLock runs on a single connection because pg advisory locks belong to the session
+func (migrator *Migrator) withLock(run func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := migrator.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1);`, migrationLockKey)
+	if err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1);`, migrationLockKey)
+
+	sqlCreate := `
+		CREATE TABLE IF NOT EXISTS public.schema_migrations (
+			version BIGINT NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (version)
+		);
+	`
+	_, err = conn.ExecContext(ctx, sqlCreate)
+	if err != nil {
+		return err
+	}
+	return run(conn)
+}
+
+func appliedVersions(conn *sql.Conn) (map[int64]bool, error) {
+	versions := map[int64]bool{}
+	rows, err := conn.QueryContext(context.Background(), `SELECT version FROM public.schema_migrations;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		err := rows.Scan(&version)
+		if err != nil {
+			return nil, err
+		}
+		versions[version] = true
+	}
+	return versions, rows.Err()
+}
+
+// execInTx runs the migration script and records it in schema_migrations atomically
+func execInTx(conn *sql.Conn, script string, sqlRecord string, args ...interface{}) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, script)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, sqlRecord, args...)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,2 @@
+DROP SCHEMA IF EXISTS todos CASCADE;
+DROP SCHEMA IF EXISTS users CASCADE;
@@ -1,3 +1,5 @@
+-- the statements are idempotent so databases created by the old
+-- ops/database/init.sql are adopted without errors
 CREATE SCHEMA IF NOT EXISTS users;
 CREATE SCHEMA IF NOT EXISTS todos;
 
@@ -42,3 +44,7 @@ CREATE TABLE IF NOT EXISTS todos.todo (
     ON UPDATE CASCADE
     ON DELETE RESTRICT
 );
+
+
+CREATE INDEX IF NOT EXISTS todo_status_user_id_idx ON todos.todo_status (user_id);
+CREATE INDEX IF NOT EXISTS todo_tstts_id_idx ON todos.todo (tstts_id);
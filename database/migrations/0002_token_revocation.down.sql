@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS users.user_token_revocation;
+DROP TABLE IF EXISTS users.revoked_token;
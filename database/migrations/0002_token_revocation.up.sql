@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS users.revoked_token (
+  token_id VARCHAR(64) NOT NULL,
+  user_id INT NOT NULL,
+  expired_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (token_id),
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS revoked_token_expired_at_idx ON users.revoked_token (expired_at);
+
+CREATE TABLE IF NOT EXISTS users.user_token_revocation (
+  user_id INT NOT NULL,
+  revoked_before TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id),
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
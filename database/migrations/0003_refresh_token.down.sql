@@ -0,0 +1 @@
+DROP TABLE IF EXISTS users.refresh_token;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS users.refresh_token (
+  id serial,
+  user_id INT NOT NULL,
+  family_id VARCHAR(64) NOT NULL,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  expired_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP DEFAULT null,
+  revoked_at TIMESTAMP DEFAULT null,
+  created_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (id),
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS refresh_token_family_id_idx ON users.refresh_token (family_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS users.user_role;
+DROP TABLE IF EXISTS users.role_permission;
+DROP TABLE IF EXISTS users.role;
@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS users.role (
+  id serial,
+  name VARCHAR(255) NOT NULL UNIQUE,
+  level_access INT UNIQUE DEFAULT null,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS users.role_permission (
+  role_id INT NOT NULL,
+  permission VARCHAR(64) NOT NULL,
+  PRIMARY KEY (role_id, permission),
+  FOREIGN KEY (role_id) 
+  	REFERENCES users.role(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS users.user_role (
+  user_id INT NOT NULL,
+  role_id INT NOT NULL,
+  PRIMARY KEY (user_id, role_id),
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (role_id) 
+  	REFERENCES users.role(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+-- default roles, one for each level access
+INSERT INTO users.role (name, level_access)
+VALUES ('basic', 1), ('manager', 2), ('admin', 3)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO users.role_permission (role_id, permission)
+SELECT r.id, p.permission
+FROM users.role r
+INNER JOIN (
+  VALUES
+    (1, 'todo:read'), (1, 'todo:write'), (1, 'todo:delete'),
+    (1, 'status:read'), (1, 'status:write'), (1, 'status:delete'),
+    (2, 'user:read'),
+    (3, 'user:admin')
+) AS p(min_level_access, permission) ON r.level_access >= p.min_level_access
+ON CONFLICT DO NOTHING;
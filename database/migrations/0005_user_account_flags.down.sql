@@ -0,0 +1,2 @@
+ALTER TABLE users.user DROP COLUMN IF EXISTS disabled;
+ALTER TABLE users.user DROP COLUMN IF EXISTS must_change_password;
@@ -0,0 +1,5 @@
+-- users that must change the password before using the api, like the genesis admin
+ALTER TABLE users.user ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- disabled users can't login, set by the admin cli
+ALTER TABLE users.user ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS todos.todo_updated_at_id_idx;
+DROP INDEX IF EXISTS todos.todo_created_at_id_idx;
@@ -0,0 +1,3 @@
+-- keyset pagination of GET /todos
+CREATE INDEX IF NOT EXISTS todo_created_at_id_idx ON todos.todo (created_at, id);
+CREATE INDEX IF NOT EXISTS todo_updated_at_id_idx ON todos.todo (updated_at, id);
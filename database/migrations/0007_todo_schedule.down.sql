@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS todos.todo_due_at_idx;
+
+ALTER TABLE todos.todo DROP COLUMN IF EXISTS due_at;
+ALTER TABLE todos.todo DROP COLUMN IF EXISTS start_at;
@@ -0,0 +1,4 @@
+ALTER TABLE todos.todo ADD COLUMN IF NOT EXISTS start_at TIMESTAMP WITH TIME ZONE DEFAULT null;
+ALTER TABLE todos.todo ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE DEFAULT null;
+
+CREATE INDEX IF NOT EXISTS todo_due_at_idx ON todos.todo (due_at);
@@ -0,0 +1,4 @@
+-- 0 none, 1 low, 2 medium, 3 high, 4 urgent
+ALTER TABLE todos.todo ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS todo_priority_idx ON todos.todo (priority);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS todos.checklist_item (
+  id serial,
+  todo_id INT NOT NULL,
+  text VARCHAR(255) NOT NULL,
+  done BOOLEAN NOT NULL DEFAULT FALSE,
+  position INT NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (id),
+  FOREIGN KEY (todo_id) 
+  	REFERENCES todos.todo(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS checklist_item_todo_id_idx ON todos.checklist_item (todo_id, position);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS todos.todo_parent_id_idx;
+ALTER TABLE todos.todo DROP CONSTRAINT IF EXISTS todo_parent_id_fkey;
+ALTER TABLE todos.todo DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,9 @@
+ALTER TABLE todos.todo ADD COLUMN IF NOT EXISTS parent_id INT NULL;
+
+ALTER TABLE todos.todo ADD CONSTRAINT todo_parent_id_fkey
+  FOREIGN KEY (parent_id)
+  REFERENCES todos.todo(id)
+  ON UPDATE CASCADE
+  ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS todo_parent_id_idx ON todos.todo (parent_id);
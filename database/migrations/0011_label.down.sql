@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS todos.todo_label;
+DROP TABLE IF EXISTS todos.label;
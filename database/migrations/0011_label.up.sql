@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS todos.label (
+  id serial,
+  user_id INT NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  color VARCHAR(7) NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (id),
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+-- names are unique per user without case, like the status names
+CREATE UNIQUE INDEX IF NOT EXISTS label_user_id_name_idx ON todos.label (user_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS todos.todo_label (
+  todo_id INT NOT NULL,
+  label_id INT NOT NULL,
+  PRIMARY KEY (todo_id, label_id),
+  FOREIGN KEY (todo_id) 
+  	REFERENCES todos.todo(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (label_id) 
+  	REFERENCES todos.label(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS todo_label_label_id_idx ON todos.todo_label (label_id);
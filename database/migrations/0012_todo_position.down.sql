@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS todos.todo_tstts_id_position_idx;
+
+ALTER TABLE todos.todo DROP COLUMN IF EXISTS position;
@@ -0,0 +1,13 @@
+-- rank of the todo on its status column, compared byte by byte
+ALTER TABLE todos.todo ADD COLUMN IF NOT EXISTS position TEXT COLLATE "C" NOT NULL DEFAULT '';
+
+-- the todos already there keep the order of creation, a rank can't end with 0
+UPDATE todos.todo t
+SET position = ranked.position
+FROM (
+  SELECT id, LPAD(TO_HEX(ROW_NUMBER() OVER (PARTITION BY tstts_id ORDER BY created_at, id)), 8, '0') || 'i' AS position
+  FROM todos.todo
+) ranked
+WHERE ranked.id = t.id;
+
+CREATE INDEX IF NOT EXISTS todo_tstts_id_position_idx ON todos.todo (tstts_id, position, id);
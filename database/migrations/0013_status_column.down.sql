@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS todos.todo_status_user_id_position_idx;
+
+ALTER TABLE todos.todo_status DROP COLUMN IF EXISTS wip_limit;
+ALTER TABLE todos.todo_status DROP COLUMN IF EXISTS color;
+ALTER TABLE todos.todo_status DROP COLUMN IF EXISTS position;
@@ -0,0 +1,15 @@
+ALTER TABLE todos.todo_status ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+ALTER TABLE todos.todo_status ADD COLUMN IF NOT EXISTS color VARCHAR(7) NOT NULL DEFAULT '#9e9e9e';
+-- max of todos on the status, NULL without limit
+ALTER TABLE todos.todo_status ADD COLUMN IF NOT EXISTS wip_limit INT NULL CHECK (wip_limit > 0);
+
+-- the statuses already there keep the order of creation
+UPDATE todos.todo_status ts
+SET position = ranked.position
+FROM (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at, id) AS position
+  FROM todos.todo_status
+) ranked
+WHERE ranked.id = ts.id;
+
+CREATE INDEX IF NOT EXISTS todo_status_user_id_position_idx ON todos.todo_status (user_id, position);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS todos.status_transition;
+
+ALTER TABLE todos.todo DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE todos.todo_status DROP COLUMN IF EXISTS category;
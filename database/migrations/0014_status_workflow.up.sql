@@ -0,0 +1,20 @@
+ALTER TABLE todos.todo_status ADD COLUMN IF NOT EXISTS category VARCHAR(20) NOT NULL DEFAULT 'todo'
+  CHECK (category IN ('backlog', 'todo', 'in-progress', 'done', 'cancelled'));
+
+-- when the todo entered a done status
+ALTER TABLE todos.todo ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP NULL;
+
+-- statuses allowed after a status, a status without rows allows every status
+CREATE TABLE IF NOT EXISTS todos.status_transition (
+  from_status_id INT NOT NULL,
+  to_status_id INT NOT NULL,
+  PRIMARY KEY (from_status_id, to_status_id),
+  FOREIGN KEY (from_status_id) 
+  	REFERENCES todos.todo_status(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (to_status_id) 
+  	REFERENCES todos.todo_status(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS todos.todo_status_board_id_position_idx;
+ALTER TABLE todos.todo_status DROP COLUMN IF EXISTS board_id;
+DROP TABLE IF EXISTS todos.board;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS todos.board (
+  id serial,
+  user_id INT NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (id),
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS board_user_id_idx ON todos.board (user_id);
+
+ALTER TABLE todos.todo_status ADD COLUMN IF NOT EXISTS board_id INT NULL
+  REFERENCES todos.board(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+-- the statuses already there go to a default board of their user
+INSERT INTO todos.board (user_id, name)
+SELECT DISTINCT user_id, 'Default'
+FROM todos.todo_status
+WHERE board_id IS NULL;
+
+UPDATE todos.todo_status ts
+SET board_id = b.id
+FROM todos.board b
+WHERE b.user_id = ts.user_id AND ts.board_id IS NULL;
+
+ALTER TABLE todos.todo_status ALTER COLUMN board_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS todo_status_board_id_position_idx ON todos.todo_status (board_id, position);
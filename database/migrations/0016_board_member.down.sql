@@ -0,0 +1,25 @@
+ALTER TABLE todos.todo_status DROP CONSTRAINT IF EXISTS todo_status_user_id_fkey;
+ALTER TABLE todos.todo_status ADD CONSTRAINT todo_status_user_id_fkey FOREIGN KEY (user_id)
+  REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+-- a board without its creator goes to its first owner
+UPDATE todos.board b
+SET user_id = (
+  SELECT bm.user_id
+  FROM todos.board_member bm
+  WHERE bm.board_id = b.id AND bm.role = 'owner'
+  ORDER BY bm.created_at, bm.user_id
+  LIMIT 1
+)
+WHERE b.user_id IS NULL;
+
+ALTER TABLE todos.board DROP CONSTRAINT IF EXISTS board_user_id_fkey;
+ALTER TABLE todos.board ADD CONSTRAINT board_user_id_fkey FOREIGN KEY (user_id)
+  REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+ALTER TABLE todos.board ALTER COLUMN user_id SET NOT NULL;
+
+DROP TABLE IF EXISTS todos.board_member;
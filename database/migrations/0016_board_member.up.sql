@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS todos.board_member (
+  board_id INT NOT NULL,
+  user_id INT NOT NULL,
+  role VARCHAR(20) NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (board_id, user_id),
+  FOREIGN KEY (board_id) 
+  	REFERENCES todos.board(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+-- the access checks look up the boards of the user
+CREATE INDEX IF NOT EXISTS board_member_user_id_idx ON todos.board_member (user_id);
+
+-- the users who created the boards own them
+INSERT INTO todos.board_member (board_id, user_id, role)
+SELECT id, user_id, 'owner'
+FROM todos.board
+ON CONFLICT DO NOTHING;
+
+-- the boards and their statuses belong to the members, they stay when the
+-- account of the user who created them is deleted
+ALTER TABLE todos.board ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE todos.board DROP CONSTRAINT IF EXISTS board_user_id_fkey;
+ALTER TABLE todos.board ADD CONSTRAINT board_user_id_fkey FOREIGN KEY (user_id)
+  REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE SET NULL;
+
+ALTER TABLE todos.todo_status DROP CONSTRAINT IF EXISTS todo_status_user_id_fkey;
+ALTER TABLE todos.todo_status ADD CONSTRAINT todo_status_user_id_fkey FOREIGN KEY (user_id)
+  REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE SET NULL;
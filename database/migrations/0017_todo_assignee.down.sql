@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS todos.todo_assignee_history;
+DROP TABLE IF EXISTS todos.todo_assignee;
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS todos.todo_assignee (
+  todo_id INT NOT NULL,
+  user_id INT NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (todo_id, user_id),
+  FOREIGN KEY (todo_id) 
+  	REFERENCES todos.todo(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+-- assignee=me
+CREATE INDEX IF NOT EXISTS todo_assignee_user_id_idx ON todos.todo_assignee (user_id);
+
+CREATE TABLE IF NOT EXISTS todos.todo_assignee_history (
+  id serial,
+  todo_id INT NOT NULL,
+  user_id INT NOT NULL,
+  -- the user who made the change, NULL after its account is deleted
+  actor_id INT NULL,
+  action VARCHAR(20) NOT NULL CHECK (action IN ('assigned', 'unassigned')),
+  created_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (id),
+  FOREIGN KEY (todo_id) 
+  	REFERENCES todos.todo(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (actor_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS todo_assignee_history_todo_id_idx ON todos.todo_assignee_history (todo_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS todos.comment_mention;
+DROP TABLE IF EXISTS todos.comment;
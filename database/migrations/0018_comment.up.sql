@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS todos.comment (
+  id serial,
+  todo_id INT NOT NULL,
+  user_id INT NOT NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (id),
+  FOREIGN KEY (todo_id) 
+  	REFERENCES todos.todo(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS comment_todo_id_idx ON todos.comment (todo_id, id);
+
+-- the mentioned users by id, so a renamed user still resolves
+CREATE TABLE IF NOT EXISTS todos.comment_mention (
+  comment_id INT NOT NULL,
+  user_id INT NOT NULL,
+  PRIMARY KEY (comment_id, user_id),
+  FOREIGN KEY (comment_id) 
+  	REFERENCES todos.comment(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS comment_mention_user_id_idx ON todos.comment_mention (user_id);
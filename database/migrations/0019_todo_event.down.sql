@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS todos.todo_assignee_history (
+  id serial,
+  todo_id INT NOT NULL,
+  user_id INT NOT NULL,
+  -- the user who made the change, NULL after its account is deleted
+  actor_id INT NULL,
+  action VARCHAR(20) NOT NULL CHECK (action IN ('assigned', 'unassigned')),
+  created_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (id),
+  FOREIGN KEY (todo_id) 
+  	REFERENCES todos.todo(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (user_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (actor_id) 
+  	REFERENCES users.user(id)
+    ON UPDATE CASCADE
+    ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS todo_assignee_history_todo_id_idx ON todos.todo_assignee_history (todo_id, created_at);
+
+INSERT INTO todos.todo_assignee_history (todo_id, user_id, actor_id, action, created_at)
+SELECT e.todo_id, COALESCE(e.changes->'assigneeId'->>'after', e.changes->'assigneeId'->>'before')::INT,
+  (SELECT u.id FROM users.user u WHERE u.id = e.actor_id), e.action, e.created_at
+FROM todos.todo_event e
+WHERE 
+  e.action IN ('assigned', 'unassigned') AND
+  EXISTS (SELECT 1 FROM todos.todo t WHERE t.id = e.todo_id) AND
+  EXISTS (SELECT 1 FROM users.user u WHERE u.id = COALESCE(e.changes->'assigneeId'->>'after', e.changes->'assigneeId'->>'before')::INT)
+ORDER BY e.id;
+
+DROP TABLE IF EXISTS todos.todo_event;
+DROP FUNCTION IF EXISTS todos.todo_event_immutable();
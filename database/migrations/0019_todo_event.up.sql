@@ -0,0 +1,37 @@
+-- no foreign keys, the history of a todo outlives the todo and its actor
+CREATE TABLE IF NOT EXISTS todos.todo_event (
+  id serial,
+  todo_id INT NOT NULL,
+  board_id INT NOT NULL,
+  actor_id INT NULL,
+  action VARCHAR(20) NOT NULL CHECK (action IN ('created', 'updated', 'moved', 'image-updated', 'image-deleted', 'deleted', 'assigned', 'unassigned')),
+  changes JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP DEFAULT NOW(),
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS todo_event_todo_id_idx ON todos.todo_event (todo_id, id);
+
+CREATE OR REPLACE FUNCTION todos.todo_event_immutable() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'todos.todo_event is immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER todo_event_immutable
+  BEFORE UPDATE OR DELETE ON todos.todo_event
+  FOR EACH ROW EXECUTE PROCEDURE todos.todo_event_immutable();
+
+INSERT INTO todos.todo_event (todo_id, board_id, actor_id, action, changes, created_at)
+SELECT h.todo_id, ts.board_id, h.actor_id, h.action,
+  CASE WHEN h.action = 'assigned'
+    THEN json_build_object('assigneeId', json_build_object('before', NULL, 'after', h.user_id))
+    ELSE json_build_object('assigneeId', json_build_object('before', h.user_id, 'after', NULL))
+  END,
+  h.created_at
+FROM todos.todo_assignee_history h
+INNER JOIN todos.todo t ON t.id = h.todo_id
+INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+ORDER BY h.id;
+
+DROP TABLE IF EXISTS todos.todo_assignee_history;
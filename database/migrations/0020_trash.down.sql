@@ -0,0 +1,23 @@
+-- the trash is emptied, its rows would come back otherwise
+DELETE FROM todos.todo WHERE deleted_at IS NOT NULL;
+DELETE FROM todos.todo_status ts
+WHERE 
+  ts.deleted_at IS NOT NULL AND
+  NOT EXISTS (SELECT 1 FROM todos.todo t WHERE t.tstts_id = ts.id);
+DELETE FROM todos.board WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE todos.todo_event DISABLE TRIGGER todo_event_immutable;
+DELETE FROM todos.todo_event WHERE action = 'restored';
+ALTER TABLE todos.todo_event ENABLE TRIGGER todo_event_immutable;
+
+ALTER TABLE todos.todo_event DROP CONSTRAINT IF EXISTS todo_event_action_check;
+ALTER TABLE todos.todo_event ADD CONSTRAINT todo_event_action_check
+  CHECK (action IN ('created', 'updated', 'moved', 'image-updated', 'image-deleted', 'deleted', 'assigned', 'unassigned'));
+
+DROP INDEX IF EXISTS todos.board_deleted_at_idx;
+DROP INDEX IF EXISTS todos.todo_status_deleted_at_idx;
+DROP INDEX IF EXISTS todos.todo_deleted_at_idx;
+
+ALTER TABLE todos.board DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE todos.todo_status DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE todos.todo DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,12 @@
+-- deleted todos and statuses stay in the trash until the purge
+ALTER TABLE todos.todo ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE todos.todo_status ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE todos.board ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS todo_deleted_at_idx ON todos.todo (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS todo_status_deleted_at_idx ON todos.todo_status (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS board_deleted_at_idx ON todos.board (deleted_at) WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE todos.todo_event DROP CONSTRAINT IF EXISTS todo_event_action_check;
+ALTER TABLE todos.todo_event ADD CONSTRAINT todo_event_action_check
+  CHECK (action IN ('created', 'updated', 'moved', 'image-updated', 'image-deleted', 'deleted', 'restored', 'assigned', 'unassigned'));
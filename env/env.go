@@ -1,9 +1,17 @@
 package env
 
 import (
+	"errors"
+	"time"
+
 	env "github.com/Netflix/go-env"
 )
 
+var (
+	ErrTrashRetentionInvalid     = errors.New("TRASH_RETENTION should be a positive duration like 720h")
+	ErrTrashPurgeIntervalInvalid = errors.New("TRASH_PURGE_INTERVAL should be a positive duration like 1h")
+)
+
 type Environment struct {
 	Database struct {
 		User     string `env:"DATABASE_USER"`
@@ -12,8 +20,32 @@ type Environment struct {
 		Password string `env:"DATABASE_PASSWORD"`
 		Dbname   string `env:"DATABASE_DBNAME"`
 		Sslmode  string `env:"DATABASE_SSLMODE"`
+		// apply the pending migrations on startup
+		AutoMigrate bool `env:"DATABASE_AUTO_MIGRATE,default=false"`
 	}
 	TokenAuthSecretKey string `env:"TOKEN_AUTH_SECRET_KEY"`
+	// when the signing key file is set tokens are signed with RS256 or EdDSA
+	// instead of the HS256 secret key
+	TokenSigning struct {
+		KeyId          string `env:"TOKEN_SIGNING_KEY_ID"`
+		KeyFile        string `env:"TOKEN_SIGNING_KEY_FILE"`
+		VerifyKeyFiles string `env:"TOKEN_VERIFY_KEY_FILES"`
+	}
+	// first admin created on an empty database, without password or password
+	// file a random password is printed once on startup
+	GenesisAdmin struct {
+		Name         string `env:"GENESIS_ADMIN_NAME,default=adm"`
+		Username     string `env:"GENESIS_ADMIN_USERNAME,default=adm"`
+		Email        string `env:"GENESIS_ADMIN_EMAIL,default=adm@email.com"`
+		Password     string `env:"GENESIS_ADMIN_PASSWORD"`
+		PasswordFile string `env:"GENESIS_ADMIN_PASSWORD_FILE"`
+	}
+	// deleted todos and statuses are purged from the trash after the
+	// retention, checked every purge interval
+	Trash struct {
+		Retention     time.Duration `env:"TRASH_RETENTION,default=720h"`
+		PurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL,default=1h"`
+	}
 }
 
 func NewEnvironment() (*Environment, error) {
@@ -22,5 +54,11 @@ func NewEnvironment() (*Environment, error) {
 	if err != nil {
 		return nil, err
 	}
+	if environment.Trash.Retention <= 0 {
+		return nil, ErrTrashRetentionInvalid
+	}
+	if environment.Trash.PurgeInterval <= 0 {
+		return nil, ErrTrashPurgeIntervalInvalid
+	}
 	return &environment, nil
 }
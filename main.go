@@ -10,8 +10,12 @@ import (
 	"api/modules/users/infra/repositories"
 	tokenjwt "api/modules/users/infra/token"
 	"api/modules/users/middlewares"
+	"api/modules/users/models"
 	"api/modules/users/usecases"
+	"fmt"
 	"time"
+	// timezones of the todo due filters on images without zoneinfo
+	_ "time/tzdata"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -38,6 +42,22 @@ func main() {
 		panic(err)
 	}
 	defer db.Close()
+	fmt.Println("[ * ] DB base connected")
+
+	// migrations
+	if env.Database.AutoMigrate {
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			panic(err)
+		}
+		applied, err := migrator.Up()
+		if err != nil {
+			panic(err)
+		}
+		for _, migration := range applied {
+			fmt.Printf("[ * ] migration %04d_%s applied\n", migration.Version, migration.Name)
+		}
+	}
 
 	// routers
 	routerPublic := gin.Default()
@@ -55,27 +75,43 @@ func main() {
 		MaxAge: 12 * time.Hour,
 	}))
 
-	// auth secret key
-	secretKey := env.TokenAuthSecretKey
+	// token maker
+	tokenMaker, err := makeTokenMaker(env)
+	if err != nil {
+		panic(err)
+	}
+	routerPublic.GET("/.well-known/jwks.json", controllers.NewKeySetController(tokenMaker).GetJWKS())
+
+	// token revocation
+	tokenRevocationRepository := repositories.NewTokenRevocationRepository(db)
+	refreshTokenRepository := repositories.NewRefreshTokenRepository(db)
+	tokenRevocation := usecases.NewTokenRevocation(tokenRevocationRepository, refreshTokenRepository)
+	tokenRevocation.StartPurge(time.Hour)
 
 	{
 		// users routes public
 		userRepository := repositories.NewUserRepository(db)
+		roleRepository := repositories.NewRoleRepository(db)
 		hashPassword := hashpassword.NewHashPassword()
 		userUsecase := usecases.NewUserUsecase(userRepository, hashPassword)
-		userController := controllers.NewUserController(userUsecase)
+		userPolicy := usecases.NewUserPolicy()
+		userController := controllers.NewUserController(userUsecase, userPolicy)
+		roleUsecase := usecases.NewRoleUsecase(roleRepository, userRepository)
+		roleController := controllers.NewRoleController(roleUsecase)
 
-		JWTMaker, err := tokenjwt.NewJWTMaker(secretKey)
-		if err != nil {
-			panic(err)
-		}
-		tokenManager := usecases.NewTokenManager(JWTMaker)
-		loginUsecase := usecases.NewTokenLoginUsecase(userRepository, hashPassword, tokenManager)
+		tokenManager := usecases.NewTokenManager(tokenMaker)
+		loginUsecase := usecases.NewTokenLoginUsecase(userRepository, roleRepository, refreshTokenRepository, hashPassword, tokenManager, tokenRevocation)
 		loginController := controllers.NewLoginController(loginUsecase)
 
 		// create user genesis
-		cliUserController := cli.NewUserController(userUsecase)
-		err = cliUserController.AddUserGenesis()
+		cliUserController := cli.NewUserController(userUsecase, roleUsecase, tokenRevocation)
+		err = cliUserController.AddUserGenesis(cli.GenesisUserConfig{
+			Name:         env.GenesisAdmin.Name,
+			Username:     env.GenesisAdmin.Username,
+			Email:        env.GenesisAdmin.Email,
+			Password:     env.GenesisAdmin.Password,
+			PasswordFile: env.GenesisAdmin.PasswordFile,
+		})
 		if err != nil {
 			panic(err)
 		}
@@ -84,15 +120,21 @@ func main() {
 
 		// login routes private
 		routerPublic.POST("/users/login", loginController.Login())
+		routerPublic.POST("/users/token/refresh", loginController.RefreshToken())
+
+		authMiddleware := middlewares.NewAuthorizationMiddleware(tokenManager, tokenRevocation)
+		{
+			// routes allowed while the user must change the password
+			passwordChangeRouter := routerPublic.Group("/")
+			passwordChangeRouter.Use(authMiddleware.AuthorizePendingPasswordChange())
+
+			passwordChangeRouter.POST("/users/logout", loginController.Logout())
+			passwordChangeRouter.POST("/users/logout/all", loginController.LogoutAll())
+			passwordChangeRouter.POST("/users/change_password/:id", userController.ChangePassword())
+		}
 
 		{
 			// users routes private
-			JWTMaker, err := tokenjwt.NewJWTMaker(secretKey)
-			if err != nil {
-				panic(err)
-			}
-			tokenManager := usecases.NewTokenManager(JWTMaker)
-			authMiddleware := middlewares.NewAuthorizationMiddleware(tokenManager)
 			userRouterPrivate := routerPublic.Group("/")
 			userRouterPrivate.Use(authMiddleware.Authorize())
 
@@ -100,50 +142,168 @@ func main() {
 			userRouterPrivate.GET("/users/:id", userController.GetUser())
 			userRouterPrivate.GET("/users", userController.GetAllUser())
 			userRouterPrivate.DELETE("/users/:id", userController.DeleteUser())
-			userRouterPrivate.POST("/users/change_password/:id", userController.ChangePassword())
 			userRouterPrivate.PATCH("/users/photo/:id", userController.UpdatePhotoUser())
 			userRouterPrivate.DELETE("/users/photo/:id", userController.DeletePhotoUser())
 			userRouterPrivate.GET("/users/photo/:id", userController.GetPhotoUser())
 
+			// roles
+			userAdmin := middlewares.RequirePermission(models.UserAdminPermission)
+			userRouterPrivate.POST("/roles", userAdmin, roleController.CreateRole())
+			userRouterPrivate.GET("/roles/:id", userAdmin, roleController.GetRole())
+			userRouterPrivate.GET("/roles", userAdmin, roleController.GetAllRole())
+			userRouterPrivate.PUT("/roles/:id", userAdmin, roleController.UpdateRole())
+			userRouterPrivate.DELETE("/roles/:id", userAdmin, roleController.DeleteRole())
+			userRouterPrivate.GET("/users/:id/roles", userAdmin, roleController.GetUserRoles())
+			userRouterPrivate.POST("/users/:id/roles", userAdmin, roleController.AssignRoleToUser())
+			userRouterPrivate.DELETE("/users/:id/roles/:roleId", userAdmin, roleController.UnassignRoleFromUser())
 		}
 	}
 
 	{
 		// todos routes private
-		JWTMaker, err := tokenjwt.NewJWTMaker(secretKey)
-		if err != nil {
-			panic(err)
-		}
-		tokenManager := usecases.NewTokenManager(JWTMaker)
-		authMiddleware := middlewares.NewAuthorizationMiddleware(tokenManager)
+		tokenManager := usecases.NewTokenManager(tokenMaker)
+		authMiddleware := middlewares.NewAuthorizationMiddleware(tokenManager, tokenRevocation)
 		todoRouterPrivate := routerPublic.Group("/")
 		todoRouterPrivate.Use(authMiddleware.Authorize())
 
 		todoRepository := todos.NewTodoRepository(db)
+		boardRepository := todos.NewBoardRepository(db)
+		historyRepository := todos.NewHistoryRepository(db)
 		userRepository := repositories.NewUserRepository(db)
 		hashPassword := hashpassword.NewHashPassword()
 		userUsecase := usecases.NewUserUsecase(userRepository, hashPassword)
-		todoUsecase := todos.NewTodoUsecase(todoRepository, userUsecase)
+		todoUsecase := todos.NewTodoUsecase(todoRepository, userUsecase, boardRepository)
 		controller := todos.NewTodoController(todoUsecase)
-		todoRouterPrivate.POST("/todos", controller.CreateTodo())
-		todoRouterPrivate.GET("/todos/:id", controller.GetTodo())
-		todoRouterPrivate.GET("/todos", controller.GetAllTodos())
-		todoRouterPrivate.PUT("/todos/:id", controller.UpdateTodo())
-		todoRouterPrivate.DELETE("/todos/:id", controller.DeleteTodo())
+		todoRead := middlewares.RequirePermission(models.TodoReadPermission)
+		todoWrite := middlewares.RequirePermission(models.TodoWritePermission)
+		todoDelete := middlewares.RequirePermission(models.TodoDeletePermission)
+		statusRead := middlewares.RequirePermission(models.StatusReadPermission)
+		statusWrite := middlewares.RequirePermission(models.StatusWritePermission)
+		statusDelete := middlewares.RequirePermission(models.StatusDeletePermission)
+
+		todoRouterPrivate.POST("/todos", todoWrite, controller.CreateTodo())
+		todoRouterPrivate.GET("/todos/:id", todoRead, controller.GetTodo())
+		todoRouterPrivate.GET("/todos", todoRead, controller.GetAllTodos())
+		todoRouterPrivate.PUT("/todos/:id", todoWrite, controller.UpdateTodo())
+		todoRouterPrivate.DELETE("/todos/:id", todoDelete, controller.DeleteTodo())
 
 		// todos image
-		todoRouterPrivate.PATCH("/todos/image/:id", controller.UpdateImageTodo())
-		todoRouterPrivate.GET("/todos/image/:id", controller.GetImageTodo())
-		todoRouterPrivate.DELETE("/todos/image/:id", controller.DeleteImageTodo())
+		todoRouterPrivate.PATCH("/todos/image/:id", todoWrite, controller.UpdateImageTodo())
+		todoRouterPrivate.GET("/todos/image/:id", todoRead, controller.GetImageTodo())
+		todoRouterPrivate.DELETE("/todos/image/:id", todoWrite, controller.DeleteImageTodo())
+
+		todoRouterPrivate.GET("/todos/:id/children", todoRead, controller.GetChildrenTodo())
+		todoRouterPrivate.PUT("/todos/:id/parent", todoWrite, controller.MoveTodo())
+		todoRouterPrivate.POST("/todos/:id/move", todoWrite, controller.PositionTodo())
+
+		// todos checklist
+		checklistRepository := todos.NewChecklistRepository(db)
+		checklistUsecase := todos.NewChecklistUsecase(checklistRepository, todoRepository)
+		checklistController := todos.NewChecklistController(checklistUsecase)
+		todoRouterPrivate.GET("/todos/:id/checklist", todoRead, checklistController.GetAllChecklistItem())
+		todoRouterPrivate.POST("/todos/:id/checklist", todoWrite, checklistController.CreateChecklistItem())
+		todoRouterPrivate.PUT("/todos/:id/checklist", todoWrite, checklistController.ReorderChecklistItems())
+		todoRouterPrivate.PATCH("/todos/:id/checklist/:itemId", todoWrite, checklistController.UpdateChecklistItem())
+		todoRouterPrivate.DELETE("/todos/:id/checklist/:itemId", todoWrite, checklistController.DeleteChecklistItem())
+
+		// labels
+		labelRepository := todos.NewLabelRepository(db)
+		labelUsecase := todos.NewLabelUsecase(labelRepository, todoRepository)
+		labelController := todos.NewLabelController(labelUsecase)
+		todoRouterPrivate.POST("/labels", todoWrite, labelController.CreateLabel())
+		todoRouterPrivate.GET("/labels/:id", todoRead, labelController.GetLabel())
+		todoRouterPrivate.GET("/labels", todoRead, labelController.GetAllLabel())
+		todoRouterPrivate.PUT("/labels/:id", todoWrite, labelController.UpdateLabel())
+		todoRouterPrivate.DELETE("/labels/:id", todoWrite, labelController.DeleteLabel())
+		todoRouterPrivate.POST("/todos/:id/labels/:labelId", todoWrite, labelController.AddTodoLabel())
+		todoRouterPrivate.DELETE("/todos/:id/labels/:labelId", todoWrite, labelController.RemoveTodoLabel())
+
+		// boards
+		boardUsecase := todos.NewBoardUsecase(boardRepository, todoRepository, userRepository)
+		boardController := todos.NewBoardController(boardUsecase)
+		todoRouterPrivate.POST("/boards", todoWrite, boardController.CreateBoard())
+		todoRouterPrivate.GET("/boards/:id", todoRead, boardController.GetBoard())
+		todoRouterPrivate.GET("/boards", todoRead, boardController.GetAllBoard())
+		todoRouterPrivate.PUT("/boards/:id", todoWrite, boardController.UpdateBoard())
+		todoRouterPrivate.DELETE("/boards/:id", todoDelete, boardController.DeleteBoard())
+		todoRouterPrivate.GET("/boards/:id/members", todoRead, boardController.GetAllBoardMember())
+		todoRouterPrivate.POST("/boards/:id/members", todoWrite, boardController.AddBoardMember())
+		todoRouterPrivate.PUT("/boards/:id/members/:userId", todoWrite, boardController.UpdateBoardMemberRole())
+		todoRouterPrivate.DELETE("/boards/:id/members/:userId", todoWrite, boardController.RemoveBoardMember())
+		todoRouterPrivate.POST("/boards/:id/leave", todoRead, boardController.LeaveBoard())
+
+		// todos assignees
+		assigneeRepository := todos.NewAssigneeRepository(db)
+		assigneeUsecase := todos.NewAssigneeUsecase(assigneeRepository, todoRepository, boardRepository)
+		assigneeController := todos.NewAssigneeController(assigneeUsecase)
+		todoRouterPrivate.POST("/todos/:id/assignees/:userId", todoWrite, assigneeController.AssignTodo())
+		todoRouterPrivate.DELETE("/todos/:id/assignees/:userId", todoWrite, assigneeController.UnassignTodo())
+
+		// todos comments
+		commentRepository := todos.NewCommentRepository(db)
+		commentUsecase := todos.NewCommentUsecase(commentRepository, todoRepository, boardRepository, userRepository)
+		commentController := todos.NewCommentController(commentUsecase)
+		todoRouterPrivate.GET("/todos/:id/comments", todoRead, commentController.GetAllComment())
+		todoRouterPrivate.POST("/todos/:id/comments", todoWrite, commentController.CreateComment())
+		todoRouterPrivate.PUT("/todos/:id/comments/:commentId", todoWrite, commentController.UpdateComment())
+		todoRouterPrivate.DELETE("/todos/:id/comments/:commentId", todoWrite, commentController.DeleteComment())
+
+		// todos history
+		historyUsecase := todos.NewHistoryUsecase(historyRepository, todoRepository)
+		historyController := todos.NewHistoryController(historyUsecase)
+		todoRouterPrivate.GET("/todos/:id/history", todoRead, historyController.GetTodoHistory())
+
+		// trash of todos and statuses
+		trashRepository := todos.NewTrashRepository(db)
+		trashUsecase := todos.NewTrashUsecase(trashRepository, env.Trash.Retention)
+		trashUsecase.StartPurge(env.Trash.PurgeInterval)
+		trashController := todos.NewTrashController(trashUsecase)
+		todoRouterPrivate.GET("/trash", todoRead, trashController.GetAllTrash())
+		todoRouterPrivate.POST("/trash/:kind/:id/restore", todoDelete, trashController.RestoreTrash())
 
 		// todo status
-		todoRouterPrivate.POST("todos/status", controller.CreateStatusTodo())
-		todoRouterPrivate.GET("todos/status/:id", controller.GetStatusTodo())
-		todoRouterPrivate.GET("todos/status", controller.GetAllStatusTodo())
-		todoRouterPrivate.PUT("todos/status/:id", controller.UpdateStatusTodo())
-		todoRouterPrivate.DELETE("todos/status/:id", controller.DeleteStatusTodo())
+		todoRouterPrivate.POST("todos/status", statusWrite, controller.CreateStatusTodo())
+		todoRouterPrivate.GET("todos/status/:id", statusRead, controller.GetStatusTodo())
+		todoRouterPrivate.GET("todos/status", statusRead, controller.GetAllStatusTodo())
+		todoRouterPrivate.PUT("todos/status/:id", statusWrite, controller.UpdateStatusTodo())
+		todoRouterPrivate.PUT("todos/status", statusWrite, controller.ReorderStatusTodo())
+		todoRouterPrivate.GET("todos/status/:id/transitions", statusRead, controller.GetStatusTransitions())
+		todoRouterPrivate.PUT("todos/status/:id/transitions", statusWrite, controller.SetStatusTransitions())
+		todoRouterPrivate.DELETE("todos/status/:id", statusDelete, controller.DeleteStatusTodo())
 	}
 
 	routerPublic.Run(":8080")
 
 }
+
+// tokenMakerWithKeySet signs tokens and publishes the keys that verify them
+type tokenMakerWithKeySet interface {
+	models.TokenMaker
+	models.KeySetProvider
+}
+
+// makeTokenMaker uses the asymmetric keyring when a signing key file is set,
+// otherwise the HS256 secret key
+func makeTokenMaker(env *env.Environment) (tokenMakerWithKeySet, error) {
+	if env.TokenSigning.KeyFile != "" {
+		keyring, err := tokenjwt.LoadKeyringFromFiles(
+			env.TokenSigning.KeyId,
+			env.TokenSigning.KeyFile,
+			env.TokenSigning.VerifyKeyFiles,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return tokenjwt.NewKeyringJWTMaker(keyring), nil
+	}
+
+	tokenMaker, err := tokenjwt.NewJWTMaker(env.TokenAuthSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	tokenMakerKeySet, ok := tokenMaker.(tokenMakerWithKeySet)
+	if !ok {
+		return nil, fmt.Errorf("token maker %T doesn't publish its key set", tokenMaker)
+	}
+	return tokenMakerKeySet, nil
+}
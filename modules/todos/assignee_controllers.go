@@ -0,0 +1,117 @@
+package todos
+
+import (
+	"api/modules/users/middlewares"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssigneeController interface {
+	AssignTodo() func(c *gin.Context)
+	UnassignTodo() func(c *gin.Context)
+}
+
+type AssigneeControllerGin struct {
+	assigneeUsecase AssigneeUsecase
+}
+
+func NewAssigneeController(assigneeUsecase AssigneeUsecase) AssigneeController {
+	return &AssigneeControllerGin{assigneeUsecase}
+}
+
+func (controller *AssigneeControllerGin) AssignTodo() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+		assigneeIdStr, hasAssigneeId := c.Params.Get("userId")
+		if !hasAssigneeId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id on url param"})
+			return
+		}
+		assigneeId, err := strconv.ParseInt(assigneeIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for assign todo")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		usecaseErr, serverErr := controller.assigneeUsecase.AssignTodo(userId, todoId, assigneeId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *AssigneeControllerGin) UnassignTodo() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+		assigneeIdStr, hasAssigneeId := c.Params.Get("userId")
+		if !hasAssigneeId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id on url param"})
+			return
+		}
+		assigneeId, err := strconv.ParseInt(assigneeIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for unassign todo")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		usecaseErr, serverErr := controller.assigneeUsecase.UnassignTodo(userId, todoId, assigneeId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
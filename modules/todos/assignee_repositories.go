@@ -0,0 +1,95 @@
+package todos
+
+import (
+	"database/sql"
+)
+
+type AssigneeRepository interface {
+	AddTodoAssignee(todoId, userId, actorId int64) error
+	RemoveTodoAssignee(todoId, userId, actorId int64) error
+}
+
+type AssigneeRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewAssigneeRepository(db *sql.DB) AssigneeRepository {
+	return &AssigneeRepositoryPG{db}
+}
+
+// AddTodoAssignee does nothing when the user is already assigned, the
+// history of the todo only records the changes
+func (repo *AssigneeRepositoryPG) AddTodoAssignee(todoId, userId, actorId int64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlInsert := `
+		INSERT INTO todos.todo_assignee (todo_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`
+	result, err := tx.Exec(sqlInsert, todoId, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		err = insertAssigneeEvent(tx, todoId, userId, actorId, TodoEventAssigned)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (repo *AssigneeRepositoryPG) RemoveTodoAssignee(todoId, userId, actorId int64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlDelete := `
+		DELETE FROM todos.todo_assignee
+		WHERE 
+			todo_id=$1 AND
+			user_id=$2;
+	`
+	result, err := tx.Exec(sqlDelete, todoId, userId)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		err = insertAssigneeEvent(tx, todoId, userId, actorId, TodoEventUnassigned)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func insertAssigneeEvent(tx *sql.Tx, todoId, userId, actorId int64, action TodoEventAction) error {
+	changes := TodoChanges{"assigneeId": {After: userId}}
+	if action == TodoEventUnassigned {
+		changes = TodoChanges{"assigneeId": {Before: userId}}
+	}
+	sqlInsert := `
+		INSERT INTO todos.todo_event (todo_id, board_id, actor_id, action, changes)
+		SELECT t.id, ts.board_id, $2, $3, $4
+		FROM todos.todo t
+		INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+		WHERE t.id=$1;
+	`
+	_, err := tx.Exec(sqlInsert, todoId, actorId, action, changes)
+	return err
+}
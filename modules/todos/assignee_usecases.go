@@ -0,0 +1,78 @@
+package todos
+
+import (
+	"errors"
+)
+
+type AssigneeUsecase interface {
+	AssignTodo(userId, todoId, assigneeId int64) (usecaseErr error, serverErr error)
+	UnassignTodo(userId, todoId, assigneeId int64) (usecaseErr error, serverErr error)
+}
+
+var (
+	ErrAssigneeNotMember = errors.New("assignee should be a member of the board of the todo")
+)
+
+type DBAssigneeUsecase struct {
+	assigneeRepository AssigneeRepository
+	todoRepository     TodoRepository
+	boardRepository    BoardRepository
+}
+
+func NewAssigneeUsecase(
+	assigneeRepository AssigneeRepository,
+	todoRepository TodoRepository,
+	boardRepository BoardRepository,
+) AssigneeUsecase {
+	return &DBAssigneeUsecase{assigneeRepository, todoRepository, boardRepository}
+}
+
+// checkTodo makes sure the todo is on a board the user changes
+func (usecase *DBAssigneeUsecase) checkTodo(userId, todoId int64) (todo *Todo, usecaseErr error, serverErr error) {
+	if todoId <= 0 {
+		usecaseErr = ErrTodoIdIsNegative
+		return
+	}
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	todo, serverErr = usecase.todoRepository.GetTodo(userId, todoId)
+	if serverErr != nil {
+		return
+	}
+	if todo == nil {
+		usecaseErr = ErrTodoNotFound
+		return
+	}
+	if !todo.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+	}
+	return
+}
+
+func (usecase *DBAssigneeUsecase) AssignTodo(userId, todoId, assigneeId int64) (usecaseErr error, serverErr error) {
+	todo, usecaseErr, serverErr := usecase.checkTodo(userId, todoId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	member, serverErr := usecase.boardRepository.GetBoardMember(todo.BoardId, assigneeId)
+	if serverErr != nil {
+		return
+	}
+	if member == nil {
+		usecaseErr = ErrAssigneeNotMember
+		return
+	}
+	serverErr = usecase.assigneeRepository.AddTodoAssignee(todoId, assigneeId, userId)
+	return
+}
+
+func (usecase *DBAssigneeUsecase) UnassignTodo(userId, todoId, assigneeId int64) (usecaseErr error, serverErr error) {
+	_, usecaseErr, serverErr = usecase.checkTodo(userId, todoId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	serverErr = usecase.assigneeRepository.RemoveTodoAssignee(todoId, assigneeId, userId)
+	return
+}
@@ -0,0 +1,454 @@
+package todos
+
+import (
+	"api/modules/users/middlewares"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BoardController interface {
+	CreateBoard() func(c *gin.Context)
+	UpdateBoard() func(c *gin.Context)
+	DeleteBoard() func(c *gin.Context)
+	GetBoard() func(c *gin.Context)
+	GetAllBoard() func(c *gin.Context)
+
+	AddBoardMember() func(c *gin.Context)
+	UpdateBoardMemberRole() func(c *gin.Context)
+	RemoveBoardMember() func(c *gin.Context)
+	LeaveBoard() func(c *gin.Context)
+	GetAllBoardMember() func(c *gin.Context)
+}
+
+type BoardControllerGin struct {
+	boardUsecase BoardUsecase
+}
+
+func NewBoardController(boardUsecase BoardUsecase) BoardController {
+	return &BoardControllerGin{boardUsecase}
+}
+
+func (controller *BoardControllerGin) CreateBoard() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		var body CreateBoardBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err := body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for create board")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		boardCreated, usecaseErr, serverErr := controller.boardUsecase.CreateBoard(userId, body.Name, body.SeedStatuses)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, boardCreated)
+	}
+}
+
+func (controller *BoardControllerGin) UpdateBoard() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id integer on url param"})
+			return
+		}
+
+		var body UpdateBoardBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for update board")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		boardUpdated, usecaseErr, serverErr := controller.boardUsecase.UpdateBoard(userId, id, body.Name)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, boardUpdated)
+	}
+}
+
+func (controller *BoardControllerGin) DeleteBoard() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for delete board")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		// the todos of the board are only deleted with cascade
+		cascade := c.Query("cascade") == "true"
+
+		deleted, usecaseErr, serverErr := controller.boardUsecase.DeleteBoard(userId, id, cascade)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		if cascade {
+			c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *BoardControllerGin) GetBoard() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for get board")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		boardFound, usecaseErr, serverErr := controller.boardUsecase.GetBoard(userId, id)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, boardFound)
+	}
+}
+
+func (controller *BoardControllerGin) GetAllBoard() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for get all board")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		boards, usecaseErr, serverErr := controller.boardUsecase.GetAllBoard(userId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, boards)
+	}
+}
+
+func (controller *BoardControllerGin) AddBoardMember() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id integer on url param"})
+			return
+		}
+
+		var body AddBoardMemberBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for add board member")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		member, usecaseErr, serverErr := controller.boardUsecase.AddBoardMember(userId, id, body.Username, body.Role)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, member)
+	}
+}
+
+func (controller *BoardControllerGin) UpdateBoardMemberRole() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id integer on url param"})
+			return
+		}
+		memberIdStr, hasMemberId := c.Params.Get("userId")
+		if !hasMemberId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id on url param"})
+			return
+		}
+		memberId, err := strconv.ParseInt(memberIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id integer on url param"})
+			return
+		}
+
+		var body UpdateBoardMemberBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for update board member")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		member, usecaseErr, serverErr := controller.boardUsecase.UpdateBoardMemberRole(userId, id, memberId, body.Role)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, member)
+	}
+}
+
+func (controller *BoardControllerGin) RemoveBoardMember() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id integer on url param"})
+			return
+		}
+		memberIdStr, hasMemberId := c.Params.Get("userId")
+		if !hasMemberId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id on url param"})
+			return
+		}
+		memberId, err := strconv.ParseInt(memberIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for remove board member")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		usecaseErr, serverErr := controller.boardUsecase.RemoveBoardMember(userId, id, memberId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *BoardControllerGin) LeaveBoard() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for leave board")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		usecaseErr, serverErr := controller.boardUsecase.LeaveBoard(userId, id)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *BoardControllerGin) GetAllBoardMember() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing board id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for get all board member")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		members, usecaseErr, serverErr := controller.boardUsecase.GetAllBoardMember(userId, id)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, members)
+	}
+}
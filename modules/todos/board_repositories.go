@@ -0,0 +1,447 @@
+package todos
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type BoardRepository interface {
+	InsertBoard(userId int64, name string, statuses []*StatusTodo) (*Board, error)
+	UpdateBoard(userId, boardId int64, name string) error
+	DeleteBoard(userId, boardId int64) (deletedTodos int64, err error)
+	GetBoard(userId, boardId int64) (*Board, error)
+	GetAllBoard(userId int64) ([]*Board, error)
+	CountTodoByBoard(boardId int64) (int64, error)
+
+	InsertBoardMember(boardId, userId int64, role BoardRole) error
+	UpdateBoardMemberRole(boardId, userId int64, role BoardRole) error
+	DeleteBoardMember(boardId, userId, actorId int64) error
+	GetBoardMember(boardId, userId int64) (*BoardMember, error)
+	GetAllBoardMember(boardId int64) ([]*BoardMember, error)
+}
+
+type BoardRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewBoardRepository(db *sql.DB) BoardRepository {
+	return &BoardRepositoryPG{db}
+}
+
+// InsertBoard creates the board owned by the user with its statuses in this order
+func (repo *BoardRepositoryPG) InsertBoard(userId int64, name string, statuses []*StatusTodo) (*Board, error) {
+	var board Board
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sqlInsert := `
+		INSERT INTO todos.board (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at;
+	`
+	err = tx.QueryRow(sqlInsert, userId, name).Scan(&board.ID, &board.CreatedAt, &board.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlInsertMember := `
+		INSERT INTO todos.board_member (board_id, user_id, role)
+		VALUES ($1, $2, $3);
+	`
+	_, err = tx.Exec(sqlInsertMember, board.ID, userId, BoardRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlInsertStatus := `
+		INSERT INTO todos.todo_status (name, user_id, board_id, color, category, position)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at;
+	`
+	for index, statusTodo := range statuses {
+		statusTodo.UserId = userId
+		statusTodo.BoardId = board.ID
+		statusTodo.Position = int64(index + 1)
+		args := []interface{}{statusTodo.Name, userId, board.ID, statusTodo.Color, statusTodo.Category, statusTodo.Position}
+		err = tx.QueryRow(sqlInsertStatus, args...).Scan(&statusTodo.ID, &statusTodo.CreatedAt, &statusTodo.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	board.UserId = userId
+	board.Name = name
+	board.Role = BoardRoleOwner
+	board.Statuses = statuses
+	return &board, nil
+}
+
+func (repo *BoardRepositoryPG) UpdateBoard(userId, boardId int64, name string) error {
+	now := time.Now().UTC()
+	sqlUpdate := `
+		UPDATE todos.board
+		SET 
+			name=$3,
+			updated_at=$4
+		WHERE 
+			id=$2 AND
+			deleted_at IS NULL AND
+			id IN (SELECT board_id FROM todos.board_member WHERE user_id=$1 AND role='owner');
+	`
+	_, err := repo.db.Exec(sqlUpdate, userId, boardId, name, now)
+	return err
+}
+
+// DeleteBoard puts the board in the trash with its statuses and todos, all
+// of them with the same deletedAt. Restoring one of them brings the board
+// back. The todos already in the trash aren't counted.
+func (repo *BoardRepositoryPG) DeleteBoard(userId, boardId int64) (int64, error) {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	err = lockBoard(tx, boardId)
+	if err != nil {
+		return 0, err
+	}
+
+	sqlDelete := `
+		UPDATE todos.board
+		SET deleted_at=$3
+		WHERE 
+			id=$1 AND
+			deleted_at IS NULL AND
+			id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2 AND role='owner');
+	`
+	result, err := tx.Exec(sqlDelete, boardId, userId, now)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return 0, err
+	}
+
+	sqlDeleteTodos := `
+		UPDATE todos.todo t
+		SET deleted_at=$2
+		FROM todos.todo_status ts
+		WHERE 
+			ts.id = t.tstts_id AND
+			ts.board_id=$1 AND
+			t.deleted_at IS NULL
+		RETURNING ` + todoEventColumns + `;
+	`
+	rows, err := tx.Query(sqlDeleteTodos, boardId, now)
+	if err != nil {
+		return 0, err
+	}
+	todos, err := scanTodoEventRows(rows)
+	if err != nil {
+		return 0, err
+	}
+	for _, todo := range todos {
+		err = insertTodoChange(tx, userId, TodoEventDeleted, todo, nil)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	sqlDeleteStatuses := `
+		UPDATE todos.todo_status
+		SET deleted_at=$2
+		WHERE 
+			board_id=$1 AND
+			deleted_at IS NULL;
+	`
+	_, err = tx.Exec(sqlDeleteStatuses, boardId, now)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(todos)), tx.Commit()
+}
+
+func (repo *BoardRepositoryPG) GetBoard(userId, boardId int64) (*Board, error) {
+	var board Board
+	sqlGet := `
+		SELECT b.id, COALESCE(b.user_id, 0), b.name, bm.role, b.created_at, b.updated_at
+		FROM todos.board b
+		INNER JOIN todos.board_member bm ON bm.board_id = b.id
+		WHERE 
+			b.id=$1 AND
+			b.deleted_at IS NULL AND
+			bm.user_id=$2;
+	`
+	row := repo.db.QueryRow(sqlGet, boardId, userId)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err := row.Scan(
+		&board.ID,
+		&board.UserId,
+		&board.Name,
+		&board.Role,
+		&board.CreatedAt,
+		&board.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &board, nil
+}
+
+func (repo *BoardRepositoryPG) GetAllBoard(userId int64) ([]*Board, error) {
+	var boards = make([]*Board, 0)
+	sqlGet := `
+		SELECT b.id, COALESCE(b.user_id, 0), b.name, bm.role, b.created_at, b.updated_at
+		FROM todos.board b
+		INNER JOIN todos.board_member bm ON bm.board_id = b.id
+		WHERE 
+			bm.user_id=$1 AND
+			b.deleted_at IS NULL
+		ORDER BY b.created_at, b.id;
+	`
+	rows, err := repo.db.Query(sqlGet, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var board Board
+		err := rows.Scan(
+			&board.ID,
+			&board.UserId,
+			&board.Name,
+			&board.Role,
+			&board.CreatedAt,
+			&board.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, &board)
+	}
+	return boards, rows.Err()
+}
+
+func (repo *BoardRepositoryPG) CountTodoByBoard(boardId int64) (int64, error) {
+	var count int64
+	sqlCount := `
+		SELECT COUNT(*)
+		FROM todos.todo t
+		INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+		WHERE ts.board_id=$1 AND t.deleted_at IS NULL
+	`
+	row := repo.db.QueryRow(sqlCount, boardId)
+	if row.Err() != nil {
+		return -1, row.Err()
+	}
+	err := row.Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+	return count, nil
+}
+
+func (repo *BoardRepositoryPG) InsertBoardMember(boardId, userId int64, role BoardRole) error {
+	sqlInsert := `
+		INSERT INTO todos.board_member (board_id, user_id, role)
+		VALUES ($1, $2, $3);
+	`
+	_, err := repo.db.Exec(sqlInsert, boardId, userId, role)
+	return err
+}
+
+// UpdateBoardMemberRole returns ErrBoardLastOwner when the member is the
+// last owner and the role isn't owner
+func (repo *BoardRepositoryPG) UpdateBoardMemberRole(boardId, userId int64, role BoardRole) error {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if role != BoardRoleOwner {
+		err = checkLastBoardOwner(tx, boardId, userId)
+		if err != nil {
+			return err
+		}
+	}
+
+	sqlUpdate := `
+		UPDATE todos.board_member
+		SET 
+			role=$3,
+			updated_at=$4
+		WHERE 
+			board_id=$1 AND
+			user_id=$2;
+	`
+	_, err = tx.Exec(sqlUpdate, boardId, userId, role, now)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteBoardMember unassigns the member from the todos of the board, it
+// returns ErrBoardLastOwner when the member is the last owner
+func (repo *BoardRepositoryPG) DeleteBoardMember(boardId, userId, actorId int64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = checkLastBoardOwner(tx, boardId, userId)
+	if err != nil {
+		return err
+	}
+
+	sqlUnassign := `
+		WITH unassigned AS (
+			DELETE FROM todos.todo_assignee
+			WHERE 
+				user_id=$2 AND
+				todo_id IN (
+					SELECT t.id
+					FROM todos.todo t
+					INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+					WHERE ts.board_id=$1
+				)
+			RETURNING todo_id, user_id
+		)
+		INSERT INTO todos.todo_event (todo_id, board_id, actor_id, action, changes)
+		SELECT todo_id, $1, $3, $4, json_build_object('assigneeId', json_build_object('before', user_id, 'after', NULL))
+		FROM unassigned;
+	`
+	_, err = tx.Exec(sqlUnassign, boardId, userId, actorId, TodoEventUnassigned)
+	if err != nil {
+		return err
+	}
+
+	sqlDelete := `
+		DELETE FROM todos.board_member
+		WHERE 
+			board_id=$1 AND
+			user_id=$2;
+	`
+	_, err = tx.Exec(sqlDelete, boardId, userId)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkLastBoardOwner locks the board so two owners can't leave it at the
+// same time
+func checkLastBoardOwner(tx *sql.Tx, boardId, userId int64) error {
+	err := lockBoard(tx, boardId)
+	if err != nil {
+		return err
+	}
+
+	var lastOwner bool
+	sqlGet := `
+		SELECT 
+			bm.role='owner' AND NOT EXISTS (
+				SELECT 1
+				FROM todos.board_member o
+				WHERE o.board_id=bm.board_id AND o.role='owner' AND o.user_id<>bm.user_id
+			)
+		FROM todos.board_member bm
+		WHERE 
+			bm.board_id=$1 AND
+			bm.user_id=$2;
+	`
+	err = tx.QueryRow(sqlGet, boardId, userId).Scan(&lastOwner)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if lastOwner {
+		return ErrBoardLastOwner
+	}
+	return nil
+}
+
+func (repo *BoardRepositoryPG) GetBoardMember(boardId, userId int64) (*BoardMember, error) {
+	var member BoardMember
+	sqlGet := `
+		SELECT bm.board_id, bm.user_id, u.name, u.username, bm.role, bm.created_at, bm.updated_at
+		FROM todos.board_member bm
+		INNER JOIN users.user u ON u.id = bm.user_id
+		WHERE 
+			bm.board_id=$1 AND
+			bm.user_id=$2;
+	`
+	row := repo.db.QueryRow(sqlGet, boardId, userId)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err := row.Scan(
+		&member.BoardId,
+		&member.UserId,
+		&member.Name,
+		&member.Username,
+		&member.Role,
+		&member.CreatedAt,
+		&member.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (repo *BoardRepositoryPG) GetAllBoardMember(boardId int64) ([]*BoardMember, error) {
+	var members = make([]*BoardMember, 0)
+	sqlGet := `
+		SELECT bm.board_id, bm.user_id, u.name, u.username, bm.role, bm.created_at, bm.updated_at
+		FROM todos.board_member bm
+		INNER JOIN users.user u ON u.id = bm.user_id
+		WHERE bm.board_id=$1
+		ORDER BY bm.created_at, bm.user_id;
+	`
+	rows, err := repo.db.Query(sqlGet, boardId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member BoardMember
+		err := rows.Scan(
+			&member.BoardId,
+			&member.UserId,
+			&member.Name,
+			&member.Username,
+			&member.Role,
+			&member.CreatedAt,
+			&member.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, &member)
+	}
+	return members, rows.Err()
+}
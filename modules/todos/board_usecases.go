@@ -0,0 +1,274 @@
+package todos
+
+import (
+	usersModels "api/modules/users/models"
+	"errors"
+)
+
+type BoardUsecase interface {
+	CreateBoard(userId int64, name string, seedStatuses bool) (board *Board, usecaseErr error, serverErr error)
+	UpdateBoard(userId, boardId int64, name string) (board *Board, usecaseErr error, serverErr error)
+	DeleteBoard(userId, boardId int64, cascade bool) (deletedTodos int64, usecaseErr error, serverErr error)
+	GetBoard(userId, boardId int64) (board *Board, usecaseErr error, serverErr error)
+	GetAllBoard(userId int64) (boards []*Board, usecaseErr error, serverErr error)
+
+	AddBoardMember(userId, boardId int64, username string, role BoardRole) (member *BoardMember, usecaseErr error, serverErr error)
+	UpdateBoardMemberRole(userId, boardId, memberId int64, role BoardRole) (member *BoardMember, usecaseErr error, serverErr error)
+	RemoveBoardMember(userId, boardId, memberId int64) (usecaseErr error, serverErr error)
+	LeaveBoard(userId, boardId int64) (usecaseErr error, serverErr error)
+	GetAllBoardMember(userId, boardId int64) (members []*BoardMember, usecaseErr error, serverErr error)
+}
+
+var (
+	ErrBoardNotFound    = errors.New("board not found")
+	ErrBoardIdNegative  = errors.New("board id should be positive")
+	ErrBoardNameInvalid = errors.New("board name should have between 1 and 255 characters")
+	ErrBoardHasTodos    = errors.New("board has todos, delete with cascade=true")
+
+	ErrBoardReadOnly            = errors.New("viewers can't change the board")
+	ErrBoardOwnerRequired       = errors.New("only the owners can change the board and its members")
+	ErrBoardLastOwner           = errors.New("the board needs another owner first")
+	ErrBoardMemberNotFound      = errors.New("board member not found")
+	ErrBoardMemberAlreadyExists = errors.New("user is already a member of the board")
+)
+
+type DBBoardUsecase struct {
+	boardRepository BoardRepository
+	todoRepository  TodoRepository
+	userRepository  usersModels.UserRepository
+}
+
+func NewBoardUsecase(
+	boardRepository BoardRepository,
+	todoRepository TodoRepository,
+	userRepository usersModels.UserRepository,
+) BoardUsecase {
+	return &DBBoardUsecase{boardRepository, todoRepository, userRepository}
+}
+
+// CreateBoard seeds the statuses To do, Doing and Done when asked
+func (usecase *DBBoardUsecase) CreateBoard(userId int64, name string, seedStatuses bool) (board *Board, usecaseErr error, serverErr error) {
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	if len(name) < 1 || len(name) > 255 {
+		usecaseErr = ErrBoardNameInvalid
+		return
+	}
+
+	statuses := make([]*StatusTodo, 0, len(defaultBoardStatuses))
+	if seedStatuses {
+		for _, defaultStatus := range defaultBoardStatuses {
+			statuses = append(statuses, &StatusTodo{
+				Name:     defaultStatus.Name,
+				Color:    StatusTodoDefaultColor,
+				Category: defaultStatus.Category,
+			})
+		}
+	}
+	board, serverErr = usecase.boardRepository.InsertBoard(userId, name, statuses)
+	return
+}
+
+func (usecase *DBBoardUsecase) UpdateBoard(userId, boardId int64, name string) (board *Board, usecaseErr error, serverErr error) {
+	if len(name) < 1 || len(name) > 255 {
+		usecaseErr = ErrBoardNameInvalid
+		return
+	}
+	board, usecaseErr, serverErr = usecase.findOwnedBoard(userId, boardId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	serverErr = usecase.boardRepository.UpdateBoard(userId, boardId, name)
+	if serverErr != nil {
+		return
+	}
+	board.Name = name
+	return
+}
+
+// DeleteBoard puts the board in the trash with its statuses, its todos only
+// with cascade
+func (usecase *DBBoardUsecase) DeleteBoard(userId, boardId int64, cascade bool) (deletedTodos int64, usecaseErr error, serverErr error) {
+	_, usecaseErr, serverErr = usecase.findOwnedBoard(userId, boardId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if !cascade {
+		countTodo, err := usecase.boardRepository.CountTodoByBoard(boardId)
+		if err != nil {
+			serverErr = err
+			return
+		}
+		if countTodo > 0 {
+			usecaseErr = ErrBoardHasTodos
+			return
+		}
+	}
+	deletedTodos, serverErr = usecase.boardRepository.DeleteBoard(userId, boardId)
+	return
+}
+
+// GetBoard returns the board with its statuses in column order
+func (usecase *DBBoardUsecase) GetBoard(userId, boardId int64) (board *Board, usecaseErr error, serverErr error) {
+	board, usecaseErr, serverErr = usecase.findBoard(userId, boardId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	board.Statuses, serverErr = usecase.todoRepository.GetAllStatusTodo(userId, boardId)
+	return
+}
+
+func (usecase *DBBoardUsecase) GetAllBoard(userId int64) (boards []*Board, usecaseErr error, serverErr error) {
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	boards, serverErr = usecase.boardRepository.GetAllBoard(userId)
+	return
+}
+
+func (usecase *DBBoardUsecase) findBoard(userId, boardId int64) (board *Board, usecaseErr error, serverErr error) {
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	if boardId <= 0 {
+		usecaseErr = ErrBoardIdNegative
+		return
+	}
+	board, serverErr = usecase.boardRepository.GetBoard(userId, boardId)
+	if serverErr != nil {
+		return
+	}
+	if board == nil {
+		usecaseErr = ErrBoardNotFound
+	}
+	return
+}
+
+func (usecase *DBBoardUsecase) findOwnedBoard(userId, boardId int64) (board *Board, usecaseErr error, serverErr error) {
+	board, usecaseErr, serverErr = usecase.findBoard(userId, boardId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if board.Role != BoardRoleOwner {
+		usecaseErr = ErrBoardOwnerRequired
+	}
+	return
+}
+
+// AddBoardMember gives the user with the username access to the board
+func (usecase *DBBoardUsecase) AddBoardMember(userId, boardId int64, username string, role BoardRole) (member *BoardMember, usecaseErr error, serverErr error) {
+	usecaseErr = role.Valid()
+	if usecaseErr != nil {
+		return
+	}
+	_, usecaseErr, serverErr = usecase.findOwnedBoard(userId, boardId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	userFound, serverErr := usecase.userRepository.GetUserByUsername(username)
+	if serverErr != nil {
+		return
+	}
+	if userFound == nil {
+		usecaseErr = ErrUserNotFound
+		return
+	}
+	memberFound, serverErr := usecase.boardRepository.GetBoardMember(boardId, userFound.ID)
+	if serverErr != nil {
+		return
+	}
+	if memberFound != nil {
+		usecaseErr = ErrBoardMemberAlreadyExists
+		return
+	}
+
+	serverErr = usecase.boardRepository.InsertBoardMember(boardId, userFound.ID, role)
+	if serverErr != nil {
+		return
+	}
+	member, serverErr = usecase.boardRepository.GetBoardMember(boardId, userFound.ID)
+	return
+}
+
+func (usecase *DBBoardUsecase) UpdateBoardMemberRole(userId, boardId, memberId int64, role BoardRole) (member *BoardMember, usecaseErr error, serverErr error) {
+	usecaseErr = role.Valid()
+	if usecaseErr != nil {
+		return
+	}
+	_, usecaseErr, serverErr = usecase.findOwnedBoard(userId, boardId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	member, usecaseErr, serverErr = usecase.findBoardMember(boardId, memberId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	serverErr = usecase.boardRepository.UpdateBoardMemberRole(boardId, memberId, role)
+	if errors.Is(serverErr, ErrBoardLastOwner) {
+		usecaseErr, serverErr = serverErr, nil
+		return
+	}
+	if serverErr != nil {
+		return
+	}
+	member.Role = role
+	return
+}
+
+// RemoveBoardMember is for the owners, the other members only remove
+// themselves. The member loses the access on the next request and the
+// todos assigned to it on the board.
+func (usecase *DBBoardUsecase) RemoveBoardMember(userId, boardId, memberId int64) (usecaseErr error, serverErr error) {
+	board, usecaseErr, serverErr := usecase.findBoard(userId, boardId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if memberId != userId && board.Role != BoardRoleOwner {
+		usecaseErr = ErrBoardOwnerRequired
+		return
+	}
+	_, usecaseErr, serverErr = usecase.findBoardMember(boardId, memberId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	serverErr = usecase.boardRepository.DeleteBoardMember(boardId, memberId, userId)
+	if errors.Is(serverErr, ErrBoardLastOwner) {
+		usecaseErr, serverErr = serverErr, nil
+	}
+	return
+}
+
+func (usecase *DBBoardUsecase) LeaveBoard(userId, boardId int64) (usecaseErr error, serverErr error) {
+	return usecase.RemoveBoardMember(userId, boardId, userId)
+}
+
+func (usecase *DBBoardUsecase) GetAllBoardMember(userId, boardId int64) (members []*BoardMember, usecaseErr error, serverErr error) {
+	_, usecaseErr, serverErr = usecase.findBoard(userId, boardId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	members, serverErr = usecase.boardRepository.GetAllBoardMember(boardId)
+	return
+}
+
+func (usecase *DBBoardUsecase) findBoardMember(boardId, memberId int64) (member *BoardMember, usecaseErr error, serverErr error) {
+	if memberId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	member, serverErr = usecase.boardRepository.GetBoardMember(boardId, memberId)
+	if serverErr != nil {
+		return
+	}
+	if member == nil {
+		usecaseErr = ErrBoardMemberNotFound
+	}
+	return
+}
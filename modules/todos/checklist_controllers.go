@@ -0,0 +1,266 @@
+package todos
+
+import (
+	"api/modules/users/middlewares"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChecklistController interface {
+	CreateChecklistItem() func(c *gin.Context)
+	UpdateChecklistItem() func(c *gin.Context)
+	DeleteChecklistItem() func(c *gin.Context)
+	GetAllChecklistItem() func(c *gin.Context)
+	ReorderChecklistItems() func(c *gin.Context)
+}
+
+type ChecklistControllerGin struct {
+	checklistUsecase ChecklistUsecase
+}
+
+func NewChecklistController(checklistUsecase ChecklistUsecase) ChecklistController {
+	return &ChecklistControllerGin{checklistUsecase}
+}
+
+func (controller *ChecklistControllerGin) CreateChecklistItem() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+
+		var body CreateChecklistItemBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for create checklist item")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		itemCreated, usecaseErr, serverErr := controller.checklistUsecase.CreateChecklistItem(userId, todoId, body.Text)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, itemCreated)
+	}
+}
+
+func (controller *ChecklistControllerGin) UpdateChecklistItem() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+		itemIdStr, hasItemId := c.Params.Get("itemId")
+		if !hasItemId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing item id on url param"})
+			return
+		}
+		itemId, err := strconv.ParseInt(itemIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing item id integer on url param"})
+			return
+		}
+
+		var body UpdateChecklistItemBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for update checklist item")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		itemUpdated, usecaseErr, serverErr := controller.checklistUsecase.UpdateChecklistItem(userId, todoId, itemId, body.Text, body.Done)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, itemUpdated)
+	}
+}
+
+func (controller *ChecklistControllerGin) DeleteChecklistItem() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+		itemIdStr, hasItemId := c.Params.Get("itemId")
+		if !hasItemId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing item id on url param"})
+			return
+		}
+		itemId, err := strconv.ParseInt(itemIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing item id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for delete checklist item")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		usecaseErr, serverErr := controller.checklistUsecase.DeleteChecklistItem(userId, todoId, itemId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *ChecklistControllerGin) GetAllChecklistItem() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for get checklist")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		items, usecaseErr, serverErr := controller.checklistUsecase.GetAllChecklistItem(userId, todoId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func (controller *ChecklistControllerGin) ReorderChecklistItems() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+
+		var body ReorderChecklistBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for reorder checklist")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		items, usecaseErr, serverErr := controller.checklistUsecase.ReorderChecklistItems(userId, todoId, body.ItemIds)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, items)
+	}
+}
@@ -0,0 +1,167 @@
+package todos
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type ChecklistRepository interface {
+	InsertChecklistItem(todoId int64, text string) (*ChecklistItem, error)
+	UpdateChecklistItem(todoId, itemId int64, text string, done bool) error
+	DeleteChecklistItem(todoId, itemId int64) error
+	GetChecklistItem(todoId, itemId int64) (*ChecklistItem, error)
+	GetAllChecklistItem(todoId int64) ([]*ChecklistItem, error)
+	ReorderChecklistItems(todoId int64, itemIds []int64) error
+}
+
+type ChecklistRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewChecklistRepository(db *sql.DB) ChecklistRepository {
+	return &ChecklistRepositoryPG{db}
+}
+
+// InsertChecklistItem adds the item at the end of the checklist
+func (repo *ChecklistRepositoryPG) InsertChecklistItem(todoId int64, text string) (*ChecklistItem, error) {
+	var item ChecklistItem
+	sqlInsert := `
+		INSERT INTO todos.checklist_item (todo_id, text, position)
+		SELECT $1, $2, COALESCE(MAX(position), 0) + 1
+		FROM todos.checklist_item
+		WHERE todo_id=$1
+		RETURNING id, position, created_at, updated_at;
+	`
+	args := []interface{}{todoId, text}
+	row := repo.db.QueryRow(sqlInsert, args...)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err := row.Scan(&item.ID, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	item.TodoId = todoId
+	item.Text = text
+	return &item, nil
+}
+
+func (repo *ChecklistRepositoryPG) UpdateChecklistItem(todoId, itemId int64, text string, done bool) error {
+	now := time.Now().UTC()
+	sqlUpdate := `
+		UPDATE todos.checklist_item
+		SET 
+			text=$3,
+			done=$4,
+			updated_at=$5
+		WHERE 
+			id=$2 AND
+			todo_id=$1;
+	`
+	args := []interface{}{todoId, itemId, text, done, now}
+	_, err := repo.db.Exec(sqlUpdate, args...)
+	return err
+}
+
+func (repo *ChecklistRepositoryPG) DeleteChecklistItem(todoId, itemId int64) error {
+	sqlDelete := `
+		DELETE FROM todos.checklist_item
+		WHERE 
+			id=$2 AND
+			todo_id=$1;
+	`
+	_, err := repo.db.Exec(sqlDelete, todoId, itemId)
+	return err
+}
+
+func (repo *ChecklistRepositoryPG) GetChecklistItem(todoId, itemId int64) (*ChecklistItem, error) {
+	var item ChecklistItem
+	sqlGet := `
+		SELECT id, todo_id, text, done, position, created_at, updated_at
+		FROM todos.checklist_item
+		WHERE 
+			id=$2 AND
+			todo_id=$1;
+	`
+	row := repo.db.QueryRow(sqlGet, todoId, itemId)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err := row.Scan(
+		&item.ID,
+		&item.TodoId,
+		&item.Text,
+		&item.Done,
+		&item.Position,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (repo *ChecklistRepositoryPG) GetAllChecklistItem(todoId int64) ([]*ChecklistItem, error) {
+	var items = make([]*ChecklistItem, 0)
+	sqlGet := `
+		SELECT id, todo_id, text, done, position, created_at, updated_at
+		FROM todos.checklist_item
+		WHERE todo_id=$1
+		ORDER BY position, id;
+	`
+	rows, err := repo.db.Query(sqlGet, todoId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item ChecklistItem
+		err := rows.Scan(
+			&item.ID,
+			&item.TodoId,
+			&item.Text,
+			&item.Done,
+			&item.Position,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+
+// ReorderChecklistItems sets the position of each item from its index on itemIds
+func (repo *ChecklistRepositoryPG) ReorderChecklistItems(todoId int64, itemIds []int64) error {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlUpdate := `
+		UPDATE todos.checklist_item
+		SET 
+			position=$3,
+			updated_at=$4
+		WHERE 
+			id=$2 AND
+			todo_id=$1;
+	`
+	for index, itemId := range itemIds {
+		_, err = tx.Exec(sqlUpdate, todoId, itemId, index+1, now)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
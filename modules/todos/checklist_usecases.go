@@ -0,0 +1,165 @@
+package todos
+
+import (
+	"errors"
+)
+
+type ChecklistUsecase interface {
+	CreateChecklistItem(userId, todoId int64, text string) (item *ChecklistItem, usecaseErr error, serverErr error)
+	UpdateChecklistItem(userId, todoId, itemId int64, text *string, done *bool) (item *ChecklistItem, usecaseErr error, serverErr error)
+	DeleteChecklistItem(userId, todoId, itemId int64) (usecaseErr error, serverErr error)
+	GetAllChecklistItem(userId, todoId int64) (items []*ChecklistItem, usecaseErr error, serverErr error)
+	ReorderChecklistItems(userId, todoId int64, itemIds []int64) (items []*ChecklistItem, usecaseErr error, serverErr error)
+}
+
+var (
+	ErrChecklistItemNotFound    = errors.New("checklist item not found")
+	ErrChecklistItemTextInvalid = errors.New("checklist item text should have between 1 and 255 characters")
+	ErrChecklistOrderInvalid    = errors.New("checklist order should have every item id once")
+)
+
+type DBChecklistUsecase struct {
+	checklistRepository ChecklistRepository
+	todoRepository      TodoRepository
+}
+
+func NewChecklistUsecase(
+	checklistRepository ChecklistRepository,
+	todoRepository TodoRepository,
+) ChecklistUsecase {
+	return &DBChecklistUsecase{checklistRepository, todoRepository}
+}
+
+// checkTodo makes sure the todo exists on a board of the user, with a role
+// that changes it when write
+func (usecase *DBChecklistUsecase) checkTodo(userId, todoId int64, write bool) (usecaseErr error, serverErr error) {
+	if todoId <= 0 {
+		usecaseErr = ErrTodoIdIsNegative
+		return
+	}
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	todoFound, serverErr := usecase.todoRepository.GetTodo(userId, todoId)
+	if serverErr != nil {
+		return
+	}
+	if todoFound == nil {
+		usecaseErr = ErrTodoNotFound
+		return
+	}
+	if write && !todoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+	}
+	return
+}
+
+func (usecase *DBChecklistUsecase) CreateChecklistItem(userId, todoId int64, text string) (item *ChecklistItem, usecaseErr error, serverErr error) {
+	if len(text) < 1 || len(text) > 255 {
+		usecaseErr = ErrChecklistItemTextInvalid
+		return
+	}
+	usecaseErr, serverErr = usecase.checkTodo(userId, todoId, true)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	item, serverErr = usecase.checklistRepository.InsertChecklistItem(todoId, text)
+	return
+}
+
+// UpdateChecklistItem changes only the informed fields, done toggles the item
+func (usecase *DBChecklistUsecase) UpdateChecklistItem(userId, todoId, itemId int64, text *string, done *bool) (item *ChecklistItem, usecaseErr error, serverErr error) {
+	if text != nil && (len(*text) < 1 || len(*text) > 255) {
+		usecaseErr = ErrChecklistItemTextInvalid
+		return
+	}
+	usecaseErr, serverErr = usecase.checkTodo(userId, todoId, true)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	item, serverErr = usecase.checklistRepository.GetChecklistItem(todoId, itemId)
+	if serverErr != nil {
+		return
+	}
+	if item == nil {
+		usecaseErr = ErrChecklistItemNotFound
+		return
+	}
+
+	if text != nil {
+		item.Text = *text
+	}
+	if done != nil {
+		item.Done = *done
+	}
+	serverErr = usecase.checklistRepository.UpdateChecklistItem(todoId, itemId, item.Text, item.Done)
+	return
+}
+
+func (usecase *DBChecklistUsecase) DeleteChecklistItem(userId, todoId, itemId int64) (usecaseErr error, serverErr error) {
+	usecaseErr, serverErr = usecase.checkTodo(userId, todoId, true)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	itemFound, serverErr := usecase.checklistRepository.GetChecklistItem(todoId, itemId)
+	if serverErr != nil {
+		return
+	}
+	if itemFound == nil {
+		usecaseErr = ErrChecklistItemNotFound
+		return
+	}
+
+	serverErr = usecase.checklistRepository.DeleteChecklistItem(todoId, itemId)
+	return
+}
+
+func (usecase *DBChecklistUsecase) GetAllChecklistItem(userId, todoId int64) (items []*ChecklistItem, usecaseErr error, serverErr error) {
+	usecaseErr, serverErr = usecase.checkTodo(userId, todoId, false)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	items, serverErr = usecase.checklistRepository.GetAllChecklistItem(todoId)
+	return
+}
+
+// ReorderChecklistItems needs the whole checklist in the new order
+func (usecase *DBChecklistUsecase) ReorderChecklistItems(userId, todoId int64, itemIds []int64) (items []*ChecklistItem, usecaseErr error, serverErr error) {
+	usecaseErr, serverErr = usecase.checkTodo(userId, todoId, true)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	items, serverErr = usecase.checklistRepository.GetAllChecklistItem(todoId)
+	if serverErr != nil {
+		return
+	}
+	if len(items) != len(itemIds) {
+		usecaseErr = ErrChecklistOrderInvalid
+		return
+	}
+	itemsById := make(map[int64]bool, len(items))
+	for _, item := range items {
+		itemsById[item.ID] = true
+	}
+	for _, itemId := range itemIds {
+		if !itemsById[itemId] {
+			usecaseErr = ErrChecklistOrderInvalid
+			return
+		}
+		// each id only once
+		delete(itemsById, itemId)
+	}
+
+	serverErr = usecase.checklistRepository.ReorderChecklistItems(todoId, itemIds)
+	if serverErr != nil {
+		return
+	}
+	items, serverErr = usecase.checklistRepository.GetAllChecklistItem(todoId)
+	return
+}
@@ -0,0 +1,228 @@
+package todos
+
+import (
+	"api/modules/users/middlewares"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentController interface {
+	CreateComment() func(c *gin.Context)
+	UpdateComment() func(c *gin.Context)
+	DeleteComment() func(c *gin.Context)
+	GetAllComment() func(c *gin.Context)
+}
+
+type CommentControllerGin struct {
+	commentUsecase CommentUsecase
+}
+
+func NewCommentController(commentUsecase CommentUsecase) CommentController {
+	return &CommentControllerGin{commentUsecase}
+}
+
+func (controller *CommentControllerGin) CreateComment() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+
+		var body CreateCommentBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for create comment")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		comment, usecaseErr, serverErr := controller.commentUsecase.CreateComment(userId, todoId, body.Body)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, comment.ToDtoHttpResponse())
+	}
+}
+
+func (controller *CommentControllerGin) UpdateComment() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+		commentIdStr, hasCommentId := c.Params.Get("commentId")
+		if !hasCommentId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing comment id on url param"})
+			return
+		}
+		commentId, err := strconv.ParseInt(commentIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing comment id integer on url param"})
+			return
+		}
+
+		var body UpdateCommentBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for update comment")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		comment, usecaseErr, serverErr := controller.commentUsecase.UpdateComment(userId, todoId, commentId, body.Body)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, comment.ToDtoHttpResponse())
+	}
+}
+
+func (controller *CommentControllerGin) DeleteComment() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+		commentIdStr, hasCommentId := c.Params.Get("commentId")
+		if !hasCommentId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing comment id on url param"})
+			return
+		}
+		commentId, err := strconv.ParseInt(commentIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing comment id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for delete comment")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		usecaseErr, serverErr := controller.commentUsecase.DeleteComment(userId, todoId, commentId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *CommentControllerGin) GetAllComment() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+
+		var params GetAllCommentQuery
+		if err := c.ShouldBindQuery(&params); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "invalid query params"})
+			return
+		}
+		cursor, err := params.CursorId()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for get all comment")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		page, usecaseErr, serverErr := controller.commentUsecase.GetAllComment(userId, todoId, cursor, params.Limit)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, page.ToDtoHttpResponse())
+	}
+}
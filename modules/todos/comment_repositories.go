@@ -0,0 +1,211 @@
+package todos
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type CommentRepository interface {
+	InsertComment(todoId, userId int64, body string, mentionIds []int64) (commentId int64, err error)
+	UpdateComment(commentId int64, body string, mentionIds []int64) error
+	DeleteComment(commentId int64) error
+	GetComment(todoId, commentId int64) (*Comment, error)
+	GetAllComment(todoId, afterId int64, limit int) ([]*Comment, error)
+	CountComment(todoId int64) (int64, error)
+}
+
+type CommentRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &CommentRepositoryPG{db}
+}
+
+// commentMentionsExpression aggregates the users mentioned on the comment c
+const commentMentionsExpression = `(
+	SELECT COALESCE(json_agg(` + userJsonExpression + ` ORDER BY u.username), '[]')
+	FROM todos.comment_mention cm
+	INNER JOIN users.user u ON u.id = cm.user_id
+	WHERE cm.comment_id = c.id
+)`
+
+func (repo *CommentRepositoryPG) InsertComment(todoId, userId int64, body string, mentionIds []int64) (int64, error) {
+	var commentId int64
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	sqlInsert := `
+		INSERT INTO todos.comment (todo_id, user_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id;
+	`
+	err = tx.QueryRow(sqlInsert, todoId, userId, body).Scan(&commentId)
+	if err != nil {
+		return 0, err
+	}
+	err = insertCommentMentions(tx, commentId, mentionIds)
+	if err != nil {
+		return 0, err
+	}
+	return commentId, tx.Commit()
+}
+
+// UpdateComment replaces the body and the mentions of the comment
+func (repo *CommentRepositoryPG) UpdateComment(commentId int64, body string, mentionIds []int64) error {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlUpdate := `
+		UPDATE todos.comment
+		SET 
+			body=$2,
+			updated_at=$3
+		WHERE id=$1;
+	`
+	_, err = tx.Exec(sqlUpdate, commentId, body, now)
+	if err != nil {
+		return err
+	}
+
+	sqlDeleteMentions := `
+		DELETE FROM todos.comment_mention
+		WHERE comment_id=$1;
+	`
+	_, err = tx.Exec(sqlDeleteMentions, commentId)
+	if err != nil {
+		return err
+	}
+	err = insertCommentMentions(tx, commentId, mentionIds)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertCommentMentions(tx *sql.Tx, commentId int64, mentionIds []int64) error {
+	if len(mentionIds) == 0 {
+		return nil
+	}
+	sqlInsert := `
+		INSERT INTO todos.comment_mention (comment_id, user_id)
+		SELECT $1, UNNEST($2::int[])
+		ON CONFLICT DO NOTHING;
+	`
+	_, err := tx.Exec(sqlInsert, commentId, pq.Array(mentionIds))
+	return err
+}
+
+func (repo *CommentRepositoryPG) DeleteComment(commentId int64) error {
+	sqlDelete := `
+		DELETE FROM todos.comment
+		WHERE id=$1;
+	`
+	_, err := repo.db.Exec(sqlDelete, commentId)
+	return err
+}
+
+func (repo *CommentRepositoryPG) GetComment(todoId, commentId int64) (*Comment, error) {
+	sqlGet := `
+		SELECT c.id, c.todo_id, u.id, u.name, u.username, CASE WHEN u.photo IS NULL THEN '' ELSE '/users/photo/' || u.id END,
+			c.body, c.created_at, c.updated_at,
+			` + commentMentionsExpression + `
+		FROM todos.comment c
+		INNER JOIN users.user u ON u.id = c.user_id
+		WHERE 
+			c.id=$1 AND
+			c.todo_id=$2;
+	`
+	row := repo.db.QueryRow(sqlGet, commentId, todoId)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	comment, err := scanComment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetAllComment returns the comments after afterId from the oldest, from
+// the first one when afterId is 0
+func (repo *CommentRepositoryPG) GetAllComment(todoId, afterId int64, limit int) ([]*Comment, error) {
+	var comments = make([]*Comment, 0)
+	sqlGet := `
+		SELECT c.id, c.todo_id, u.id, u.name, u.username, CASE WHEN u.photo IS NULL THEN '' ELSE '/users/photo/' || u.id END,
+			c.body, c.created_at, c.updated_at,
+			` + commentMentionsExpression + `
+		FROM todos.comment c
+		INNER JOIN users.user u ON u.id = c.user_id
+		WHERE 
+			c.todo_id=$1 AND
+			c.id > $2
+		ORDER BY c.id
+		LIMIT $3;
+	`
+	rows, err := repo.db.Query(sqlGet, todoId, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+func scanComment(row interface{ Scan(...interface{}) error }) (*Comment, error) {
+	var comment Comment
+	err := row.Scan(
+		&comment.ID,
+		&comment.TodoId,
+		&comment.Author.ID,
+		&comment.Author.Name,
+		&comment.Author.Username,
+		&comment.Author.PhotoUrl,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.Mentions,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (repo *CommentRepositoryPG) CountComment(todoId int64) (int64, error) {
+	var count int64
+	sqlCount := `
+		SELECT COUNT(*)
+		FROM todos.comment
+		WHERE todo_id=$1
+	`
+	row := repo.db.QueryRow(sqlCount, todoId)
+	if row.Err() != nil {
+		return -1, row.Err()
+	}
+	err := row.Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+	return count, nil
+}
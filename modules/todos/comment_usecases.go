@@ -0,0 +1,218 @@
+package todos
+
+import (
+	usersModels "api/modules/users/models"
+	"errors"
+	"unicode/utf8"
+)
+
+type CommentUsecase interface {
+	CreateComment(userId, todoId int64, body string) (comment *Comment, usecaseErr error, serverErr error)
+	UpdateComment(userId, todoId, commentId int64, body string) (comment *Comment, usecaseErr error, serverErr error)
+	DeleteComment(userId, todoId, commentId int64) (usecaseErr error, serverErr error)
+	GetAllComment(userId, todoId, cursor int64, limit int) (page *CommentPage, usecaseErr error, serverErr error)
+}
+
+var (
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrCommentIdNegative   = errors.New("comment id should be positive")
+	ErrCommentBodyInvalid  = errors.New("comment body should have between 1 and 5000 characters")
+	ErrCommentAuthorOnly   = errors.New("only the author can edit the comment")
+	ErrCommentDeleteDenied = errors.New("only the author or the owners of the board can delete the comment")
+	ErrCommentLimitInvalid = errors.New("limit should be between 1 and 100")
+)
+
+type DBCommentUsecase struct {
+	commentRepository CommentRepository
+	todoRepository    TodoRepository
+	boardRepository   BoardRepository
+	userRepository    usersModels.UserRepository
+}
+
+func NewCommentUsecase(
+	commentRepository CommentRepository,
+	todoRepository TodoRepository,
+	boardRepository BoardRepository,
+	userRepository usersModels.UserRepository,
+) CommentUsecase {
+	return &DBCommentUsecase{commentRepository, todoRepository, boardRepository, userRepository}
+}
+
+// checkTodo makes sure the todo is on a board of the user
+func (usecase *DBCommentUsecase) checkTodo(userId, todoId int64) (todo *Todo, usecaseErr error, serverErr error) {
+	if todoId <= 0 {
+		usecaseErr = ErrTodoIdIsNegative
+		return
+	}
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	todo, serverErr = usecase.todoRepository.GetTodo(userId, todoId)
+	if serverErr != nil {
+		return
+	}
+	if todo == nil {
+		usecaseErr = ErrTodoNotFound
+	}
+	return
+}
+
+func (usecase *DBCommentUsecase) findComment(todoId, commentId int64) (comment *Comment, usecaseErr error, serverErr error) {
+	if commentId <= 0 {
+		usecaseErr = ErrCommentIdNegative
+		return
+	}
+	comment, serverErr = usecase.commentRepository.GetComment(todoId, commentId)
+	if serverErr != nil {
+		return
+	}
+	if comment == nil {
+		usecaseErr = ErrCommentNotFound
+	}
+	return
+}
+
+// resolveMentions returns the ids of the members of the board mentioned on
+// the body, the other usernames stay as text
+func (usecase *DBCommentUsecase) resolveMentions(boardId int64, body string) (mentionIds []int64, serverErr error) {
+	mentionIds = make([]int64, 0)
+	usernames := ParseMentions(body)
+	if len(usernames) > commentMaxMentions {
+		usernames = usernames[:commentMaxMentions]
+	}
+	for _, username := range usernames {
+		userFound, err := usecase.userRepository.GetUserByUsername(username)
+		if err != nil {
+			serverErr = err
+			return
+		}
+		if userFound == nil {
+			continue
+		}
+		member, err := usecase.boardRepository.GetBoardMember(boardId, userFound.ID)
+		if err != nil {
+			serverErr = err
+			return
+		}
+		if member != nil {
+			mentionIds = append(mentionIds, userFound.ID)
+		}
+	}
+	return
+}
+
+func validCommentBody(body string) error {
+	length := utf8.RuneCountInString(body)
+	if length < 1 || length > 5000 {
+		return ErrCommentBodyInvalid
+	}
+	return nil
+}
+
+// CreateComment is for the members who change the board
+func (usecase *DBCommentUsecase) CreateComment(userId, todoId int64, body string) (comment *Comment, usecaseErr error, serverErr error) {
+	usecaseErr = validCommentBody(body)
+	if usecaseErr != nil {
+		return
+	}
+	todo, usecaseErr, serverErr := usecase.checkTodo(userId, todoId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if !todo.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
+
+	mentionIds, serverErr := usecase.resolveMentions(todo.BoardId, body)
+	if serverErr != nil {
+		return
+	}
+	commentId, serverErr := usecase.commentRepository.InsertComment(todoId, userId, body, mentionIds)
+	if serverErr != nil {
+		return
+	}
+	comment, serverErr = usecase.commentRepository.GetComment(todoId, commentId)
+	return
+}
+
+func (usecase *DBCommentUsecase) UpdateComment(userId, todoId, commentId int64, body string) (comment *Comment, usecaseErr error, serverErr error) {
+	usecaseErr = validCommentBody(body)
+	if usecaseErr != nil {
+		return
+	}
+	todo, usecaseErr, serverErr := usecase.checkTodo(userId, todoId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	comment, usecaseErr, serverErr = usecase.findComment(todoId, commentId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if comment.Author.ID != userId {
+		usecaseErr = ErrCommentAuthorOnly
+		return
+	}
+
+	mentionIds, serverErr := usecase.resolveMentions(todo.BoardId, body)
+	if serverErr != nil {
+		return
+	}
+	serverErr = usecase.commentRepository.UpdateComment(commentId, body, mentionIds)
+	if serverErr != nil {
+		return
+	}
+	comment, serverErr = usecase.commentRepository.GetComment(todoId, commentId)
+	return
+}
+
+func (usecase *DBCommentUsecase) DeleteComment(userId, todoId, commentId int64) (usecaseErr error, serverErr error) {
+	todo, usecaseErr, serverErr := usecase.checkTodo(userId, todoId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	comment, usecaseErr, serverErr := usecase.findComment(todoId, commentId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if comment.Author.ID != userId && todo.Role != BoardRoleOwner {
+		usecaseErr = ErrCommentDeleteDenied
+		return
+	}
+	serverErr = usecase.commentRepository.DeleteComment(commentId)
+	return
+}
+
+// GetAllComment returns the comments after the cursor, the id of the last
+// comment of the previous page
+func (usecase *DBCommentUsecase) GetAllComment(userId, todoId, cursor int64, limit int) (page *CommentPage, usecaseErr error, serverErr error) {
+	if limit == 0 {
+		limit = CommentDefaultLimit
+	}
+	if limit < 1 || limit > CommentMaxLimit {
+		usecaseErr = ErrCommentLimitInvalid
+		return
+	}
+	_, usecaseErr, serverErr = usecase.checkTodo(userId, todoId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	// one more comment says there is a next page
+	comments, serverErr := usecase.commentRepository.GetAllComment(todoId, cursor, limit+1)
+	if serverErr != nil {
+		return
+	}
+	total, serverErr := usecase.commentRepository.CountComment(todoId)
+	if serverErr != nil {
+		return
+	}
+	page = &CommentPage{Comments: comments, Total: total}
+	if len(comments) > limit {
+		page.Comments = comments[:limit]
+		nextCursor := page.Comments[limit-1].ID
+		page.NextCursor = &nextCursor
+	}
+	return
+}
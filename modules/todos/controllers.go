@@ -2,10 +2,10 @@ package todos
 
 import (
 	"api/modules/users/middlewares"
-	"api/modules/users/models"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +17,10 @@ type TodoController interface {
 	GetTodo() func(c *gin.Context)
 	GetAllTodos() func(c *gin.Context)
 
+	GetChildrenTodo() func(c *gin.Context)
+	MoveTodo() func(c *gin.Context)
+	PositionTodo() func(c *gin.Context)
+
 	GetImageTodo() func(c *gin.Context)
 	UpdateImageTodo() func(c *gin.Context)
 	DeleteImageTodo() func(c *gin.Context)
@@ -26,6 +30,9 @@ type TodoController interface {
 	GetStatusTodo() func(c *gin.Context)
 	GetAllStatusTodo() func(c *gin.Context)
 	DeleteStatusTodo() func(c *gin.Context)
+	ReorderStatusTodo() func(c *gin.Context)
+	GetStatusTransitions() func(c *gin.Context)
+	SetStatusTransitions() func(c *gin.Context)
 }
 
 type TodoControllerGin struct {
@@ -60,29 +67,14 @@ func (controller *TodoControllerGin) CreateTodo() func(c *gin.Context) {
 		}
 		userId := userIdValue.(int64)
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
-		if !exists {
-			fmt.Println("need level access for create status todo")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
-			return
-		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
-
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
-			return
-		}
-
-		todoCreated, usecaseErr, serverErr := controller.todoUsecase.CreateTodo(body.Title, body.Description, body.StatusID, userId)
+		todoCreated, usecaseErr, serverErr := controller.todoUsecase.CreateTodo(body.Title, body.Description, body.StatusID, userId, body.StartAt, body.DueAt, body.Priority, body.ParentId)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 		c.JSON(http.StatusCreated, todoCreated.ToDtoHttpResponse())
@@ -103,29 +95,23 @@ func (controller *TodoControllerGin) GetImageTodo() func(c *gin.Context) {
 			return
 		}
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
 		if !exists {
-			fmt.Println("need level access for create status todo")
+			fmt.Println("need user id for create status todo")
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
-
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
-			return
-		}
+		userId := userIdValue.(int64)
 
-		bufferImage, usecaseErr, serverErr := controller.todoUsecase.GetImageTodo(id)
+		bufferImage, usecaseErr, serverErr := controller.todoUsecase.GetImageTodo(userId, id)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 		if bufferImage.Len() == 0 {
@@ -152,20 +138,14 @@ func (controller *TodoControllerGin) UpdateImageTodo() func(c *gin.Context) {
 			return
 		}
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
 		if !exists {
-			fmt.Println("need level access for create status todo")
+			fmt.Println("need user id for create status todo")
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
-
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
-			return
-		}
+		userId := userIdValue.(int64)
 
 		// get image
 		fileHeader, _ := c.FormFile("image")
@@ -181,14 +161,14 @@ func (controller *TodoControllerGin) UpdateImageTodo() func(c *gin.Context) {
 		}
 
 		// update
-		usecaseErr, serverErr := controller.todoUsecase.UpdateImageTodo(updateImageTodoFile)
+		usecaseErr, serverErr := controller.todoUsecase.UpdateImageTodo(userId, updateImageTodoFile)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 
@@ -209,29 +189,23 @@ func (controller *TodoControllerGin) DeleteImageTodo() func(c *gin.Context) {
 			return
 		}
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
 		if !exists {
-			fmt.Println("need level access for create status todo")
+			fmt.Println("need user id for create status todo")
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
-
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
-			return
-		}
+		userId := userIdValue.(int64)
 
-		usecaseErr, serverErr := controller.todoUsecase.DeleteImageTodo(id)
+		usecaseErr, serverErr := controller.todoUsecase.DeleteImageTodo(userId, id)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 
@@ -274,37 +248,66 @@ func (controller *TodoControllerGin) UpdateTodo() func(c *gin.Context) {
 		}
 		userId := userIdValue.(int64)
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
-		if !exists {
-			fmt.Println("need level access for create status todo")
+		usecaseErr, serverErr := controller.todoUsecase.UpdateTodo(id, body.Title, body.Description, body.StatusID, userId, body.StartAt, body.DueAt, body.Priority)
+		if serverErr != nil {
+			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
 
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *TodoControllerGin) DeleteTodo() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
 			return
 		}
 
-		usecaseErr, serverErr := controller.todoUsecase.UpdateTodo(id, body.Title, body.Description, body.StatusID, userId)
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for create status todo")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		// what to do with the children, required when the todo has some
+		childrenMode := ChildrenDeleteMode(c.Query("children"))
+
+		deleted, usecaseErr, serverErr := controller.todoUsecase.DeleteTodo(userId, id, childrenMode)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 
-		c.Status(http.StatusNoContent)
+		if childrenMode != "" {
+			c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+			return
+		}
+		c.JSON(http.StatusNoContent, nil)
 	}
 }
 
-func (controller *TodoControllerGin) DeleteTodo() func(c *gin.Context) {
+func (controller *TodoControllerGin) GetChildrenTodo() func(c *gin.Context) {
 	return func(c *gin.Context) {
 		idStr, hasId := c.Params.Get("id")
 		if !hasId {
@@ -317,37 +320,38 @@ func (controller *TodoControllerGin) DeleteTodo() func(c *gin.Context) {
 			return
 		}
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
 		if !exists {
-			fmt.Println("need level access for create status todo")
+			fmt.Println("need user id for get children todo")
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
+		userId := userIdValue.(int64)
 
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
-			return
-		}
+		// the whole subtree or only the direct children
+		recursive := c.Query("recursive") == "true"
 
-		usecaseErr, serverErr := controller.todoUsecase.DeleteTodo(id)
+		children, usecaseErr, serverErr := controller.todoUsecase.GetChildrenTodo(userId, id, recursive)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 
-		c.JSON(http.StatusNoContent, nil)
+		childrenDto := make([]*TodoDtoHttpResponse, 0, len(children))
+		for _, child := range children {
+			childrenDto = append(childrenDto, child.ToDtoHttpResponse())
+		}
+		c.JSON(http.StatusOK, childrenDto)
 	}
 }
 
-func (controller *TodoControllerGin) GetTodo() func(c *gin.Context) {
+func (controller *TodoControllerGin) MoveTodo() func(c *gin.Context) {
 	return func(c *gin.Context) {
 		idStr, hasId := c.Params.Get("id")
 		if !hasId {
@@ -360,29 +364,66 @@ func (controller *TodoControllerGin) GetTodo() func(c *gin.Context) {
 			return
 		}
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
+		var body MoveTodoBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
 		if !exists {
-			fmt.Println("need level access for create status todo")
+			fmt.Println("need user id for move todo")
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
+		userId := userIdValue.(int64)
+
+		usecaseErr, serverErr := controller.todoUsecase.MoveTodo(userId, id, body.ParentId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *TodoControllerGin) GetTodo() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
 
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for create status todo")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
+		userId := userIdValue.(int64)
 
-		todoFound, usecaseErr, serverErr := controller.todoUsecase.GetTodo(id)
+		todoFound, usecaseErr, serverErr := controller.todoUsecase.GetTodo(userId, id)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 		if todoFound == nil {
@@ -396,38 +437,40 @@ func (controller *TodoControllerGin) GetTodo() func(c *gin.Context) {
 
 func (controller *TodoControllerGin) GetAllTodos() func(c *gin.Context) {
 	return func(c *gin.Context) {
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
 		if !exists {
-			fmt.Println("need level access for create status todo")
+			fmt.Println("need user id for create status todo")
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
+		userId := userIdValue.(int64)
 
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
+		// get filters, sort and page
+		var params GetAllTodoQuery
+		if err := c.ShouldBindQuery(&params); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "invalid query params"})
+			return
+		}
+		params.ProcessData()
+		query, err := params.ToTodoQuery(userId, time.Now())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 			return
 		}
 
-		todos, usecaseErr, serverErr := controller.todoUsecase.GetAllTodo()
+		page, usecaseErr, serverErr := controller.todoUsecase.GetAllTodo(query)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 
-		var wrappedResponse []*TodoDtoHttpResponse = make([]*TodoDtoHttpResponse, 0)
-		for _, todo := range todos {
-			wrappedResponse = append(wrappedResponse, todo.ToDtoHttpResponse())
-		}
-
-		c.JSON(http.StatusOK, wrappedResponse)
+		c.JSON(http.StatusOK, page.ToDtoHttpResponse())
 	}
 }
 
@@ -454,30 +497,15 @@ func (controller *TodoControllerGin) CreateStatusTodo() func(c *gin.Context) {
 		}
 		userId := userIdValue.(int64)
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
-		if !exists {
-			fmt.Println("need level access for create status todo")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
-			return
-		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
-
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
-			return
-		}
-
 		// create status todo
-		statusTodoCreated, usecaseErr, serverErr := controller.todoUsecase.CreateStatusTodo(body.Name, userId)
+		statusTodoCreated, usecaseErr, serverErr := controller.todoUsecase.CreateStatusTodo(body.Name, userId, body.BoardId, body.Color, body.WipLimit, body.Category)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 
@@ -500,8 +528,7 @@ func (controller *TodoControllerGin) UpdateStatusTodo() func(c *gin.Context) {
 			return
 		}
 
-		// TODO: mudar para updatestatusTodobody
-		var body CreateStatusTodoBody
+		var body UpdateStatusTodoBody
 		if err := c.ShouldBindJSON(&body); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
 			return
@@ -518,29 +545,14 @@ func (controller *TodoControllerGin) UpdateStatusTodo() func(c *gin.Context) {
 		}
 		userId := userIdValue.(int64)
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
-		if !exists {
-			fmt.Println("need level access for create status todo")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
-			return
-		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
-
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
-			return
-		}
-
-		usecaseErr, serverErr := controller.todoUsecase.UpdateStatusTodo(userId, statusId, body.Name)
+		usecaseErr, serverErr := controller.todoUsecase.UpdateStatusTodo(userId, statusId, body.Name, body.Color, body.WipLimit, body.Category)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 
@@ -550,29 +562,33 @@ func (controller *TodoControllerGin) UpdateStatusTodo() func(c *gin.Context) {
 
 func (controller *TodoControllerGin) GetAllStatusTodo() func(c *gin.Context) {
 	return func(c *gin.Context) {
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
 		if !exists {
-			fmt.Println("need level access for create status todo")
+			fmt.Println("need user id for create status todo")
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
+		userId := userIdValue.(int64)
 
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
-			return
+		var boardId int64
+		if boardIdStr := c.Query("boardId"); boardIdStr != "" {
+			var err error
+			boardId, err = strconv.ParseInt(boardIdStr, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "boardId should be an integer"})
+				return
+			}
 		}
 
-		allStatusTodo, usecaseErr, serverErr := controller.todoUsecase.GetAllStatusTodo()
+		allStatusTodo, usecaseErr, serverErr := controller.todoUsecase.GetAllStatusTodo(userId, boardId)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 		c.JSON(http.StatusOK, allStatusTodo)
@@ -601,21 +617,6 @@ func (controller *TodoControllerGin) GetStatusTodo() func(c *gin.Context) {
 		}
 		userId := userIdValue.(int64)
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
-		if !exists {
-			fmt.Println("need level access for create status todo")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
-			return
-		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
-
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
-			return
-		}
-
 		statusTodoFound, usecaseErr, serverErr := controller.todoUsecase.GetStatusTodo(userId, id)
 		if serverErr != nil {
 			fmt.Println(serverErr)
@@ -623,7 +624,7 @@ func (controller *TodoControllerGin) GetStatusTodo() func(c *gin.Context) {
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 		if statusTodoFound == nil {
@@ -657,32 +658,216 @@ func (controller *TodoControllerGin) DeleteStatusTodo() func(c *gin.Context) {
 		}
 		userId := userIdValue.(int64)
 
-		// get level access
-		levelAccessValue, exists := c.Get(middlewares.LevelAccess)
+		// what to do with the todos of the status, required when it has some
+		var moveTo int64
+		if moveToStr := c.Query("moveTo"); moveToStr != "" {
+			moveTo, err = strconv.ParseInt(moveToStr, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "moveTo should be a status id"})
+				return
+			}
+		}
+		cascade := c.Query("cascade") == "true"
+
+		affected, usecaseErr, serverErr := controller.todoUsecase.DeleteStatusTodo(userId, id, moveTo, cascade)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		if cascade {
+			c.JSON(http.StatusOK, gin.H{"deleted": affected})
+			return
+		}
+		if moveTo != 0 {
+			c.JSON(http.StatusOK, gin.H{"moved": affected})
+			return
+		}
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
+
+func (controller *TodoControllerGin) PositionTodo() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+
+		var body PositionTodoBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
 		if !exists {
-			fmt.Println("need level access for create status todo")
+			fmt.Println("need user id for position todo")
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
-		levelAccess := levelAccessValue.(models.LevelAccess)
+		userId := userIdValue.(int64)
 
-		// check authorization
-		if levelAccess < models.BasicLevelAccess {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "you don't have authorization"})
+		todoMoved, usecaseErr, serverErr := controller.todoUsecase.PositionTodo(userId, id, body.StatusID, body.PreviousId, body.NextId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 
-		usecaseErr, serverErr := controller.todoUsecase.DeleteStatusTodo(userId, id)
+		c.JSON(http.StatusOK, todoMoved.ToDtoHttpResponse())
+	}
+}
+
+func (controller *TodoControllerGin) ReorderStatusTodo() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		var body ReorderStatusTodoBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		err := body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for reorder status todo")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		allStatusTodo, usecaseErr, serverErr := controller.todoUsecase.ReorderStatusTodo(userId, body.BoardId, body.StatusIds)
 		if serverErr != nil {
 			fmt.Println(serverErr)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
 		}
 		if usecaseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
 			return
 		}
 
-		c.JSON(http.StatusNoContent, nil)
+		c.JSON(http.StatusOK, allStatusTodo)
+	}
+}
+
+func (controller *TodoControllerGin) GetStatusTransitions() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing status todo id on url param"})
+			return
+		}
+		statusId, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing status todo id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for get status transitions")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		toStatus, usecaseErr, serverErr := controller.todoUsecase.GetStatusTransitions(userId, statusId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, toStatus)
+	}
+}
+
+func (controller *TodoControllerGin) SetStatusTransitions() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing status todo id on url param"})
+			return
+		}
+		statusId, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing status todo id integer on url param"})
+			return
+		}
+
+		var body StatusTransitionsBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for set status transitions")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		toStatus, usecaseErr, serverErr := controller.todoUsecase.SetStatusTransitions(userId, statusId, body.ToStatusIds)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, toStatus)
+	}
+}
+
+// usecaseErrStatusCode answers 404 for rows that don't exist or belong to
+// another user and 409 when the move was made on an outdated column, the
+// column is full or the workflow doesn't allow it
+func usecaseErrStatusCode(usecaseErr error) int {
+	switch usecaseErr {
+	case ErrTodoMoveConflict, ErrStatusWipLimitReached, ErrStatusTransitionDenied, ErrBoardHasTodos, ErrBoardLastOwner, ErrBoardMemberAlreadyExists, ErrTrashStatusNameTaken:
+		return http.StatusConflict
+	case ErrTodoNotFound, ErrStatusTodoNotFound, ErrImageNotFound, ErrChecklistItemNotFound, ErrParentTodoNotFound, ErrLabelNotFound, ErrTodoNeighborNotFound, ErrBoardNotFound, ErrBoardMemberNotFound, ErrUserNotFound, ErrCommentNotFound, ErrTrashItemNotFound:
+		return http.StatusNotFound
+	case ErrBoardReadOnly, ErrBoardOwnerRequired, ErrCommentAuthorOnly, ErrCommentDeleteDenied:
+		return http.StatusForbidden
 	}
+	return http.StatusBadRequest
 }
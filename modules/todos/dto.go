@@ -4,13 +4,19 @@ import (
 	"bytes"
 	"errors"
 	"mime/multipart"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type CreateTodoBody struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	StatusID    int64  `json:"statusId"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	StatusID    int64      `json:"statusId"`
+	StartAt     *time.Time `json:"startAt"`
+	DueAt       *time.Time `json:"dueAt"`
+	Priority    Priority   `json:"priority"`
+	ParentId    *int64     `json:"parentId"`
 }
 
 func (body *CreateTodoBody) Validate() error {
@@ -29,9 +35,12 @@ func (body *CreateTodoBody) ProcessData() {
 }
 
 type UpdateTodoBody struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	StatusID    int64  `json:"statusId"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	StatusID    int64      `json:"statusId"`
+	StartAt     *time.Time `json:"startAt"`
+	DueAt       *time.Time `json:"dueAt"`
+	Priority    Priority   `json:"priority"`
 }
 
 func (body *UpdateTodoBody) Validate() error {
@@ -49,11 +58,393 @@ func (body *UpdateTodoBody) ProcessData() {
 	body.Description = strings.TrimSpace(body.Description)
 }
 
-type CreateStatusTodoBody struct {
+type CreateLabelBody struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func (body *CreateLabelBody) Validate() error {
+	if body.Name == "" {
+		return errors.New("missing name")
+	}
+	if body.Color == "" {
+		return errors.New("missing color")
+	}
+	return nil
+}
+
+func (body *CreateLabelBody) ProcessData() {
+	body.Name = strings.TrimSpace(body.Name)
+	body.Color = strings.ToLower(strings.TrimSpace(body.Color))
+}
+
+type UpdateLabelBody struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func (body *UpdateLabelBody) Validate() error {
+	if body.Name == "" {
+		return errors.New("missing name")
+	}
+	if body.Color == "" {
+		return errors.New("missing color")
+	}
+	return nil
+}
+
+func (body *UpdateLabelBody) ProcessData() {
+	body.Name = strings.TrimSpace(body.Name)
+	body.Color = strings.ToLower(strings.TrimSpace(body.Color))
+}
+
+type CreateBoardBody struct {
 	Name string `json:"name"`
+	// SeedStatuses creates the statuses To do, Doing and Done
+	SeedStatuses bool `json:"seedStatuses"`
+}
+
+func (body *CreateBoardBody) Validate() error {
+	if body.Name == "" {
+		return errors.New("missing name")
+	}
+	return nil
+}
+
+func (body *CreateBoardBody) ProcessData() {
+	body.Name = strings.TrimSpace(body.Name)
+}
+
+type UpdateBoardBody struct {
+	Name string `json:"name"`
+}
+
+func (body *UpdateBoardBody) Validate() error {
+	if body.Name == "" {
+		return errors.New("missing name")
+	}
+	return nil
+}
+
+func (body *UpdateBoardBody) ProcessData() {
+	body.Name = strings.TrimSpace(body.Name)
+}
+
+type AddBoardMemberBody struct {
+	Username string    `json:"username"`
+	Role     BoardRole `json:"role"`
+}
+
+func (body *AddBoardMemberBody) Validate() error {
+	if body.Username == "" {
+		return errors.New("missing username")
+	}
+	if body.Role == "" {
+		return errors.New("missing role")
+	}
+	return nil
+}
+
+func (body *AddBoardMemberBody) ProcessData() {
+	body.Username = strings.TrimSpace(body.Username)
+}
+
+type UpdateBoardMemberBody struct {
+	Role BoardRole `json:"role"`
+}
+
+func (body *UpdateBoardMemberBody) Validate() error {
+	if body.Role == "" {
+		return errors.New("missing role")
+	}
+	return nil
+}
+
+type CreateCommentBody struct {
+	Body string `json:"body"`
+}
+
+func (body *CreateCommentBody) Validate() error {
+	if body.Body == "" {
+		return errors.New("missing body")
+	}
+	return nil
+}
+
+func (body *CreateCommentBody) ProcessData() {
+	body.Body = strings.TrimSpace(body.Body)
+}
+
+type UpdateCommentBody struct {
+	Body string `json:"body"`
+}
+
+func (body *UpdateCommentBody) Validate() error {
+	if body.Body == "" {
+		return errors.New("missing body")
+	}
+	return nil
+}
+
+func (body *UpdateCommentBody) ProcessData() {
+	body.Body = strings.TrimSpace(body.Body)
+}
+
+// GetAllCommentQuery is the url query of GET /todos/:id/comments, the
+// cursor is the nextCursor of the previous page
+type GetAllCommentQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+func (params *GetAllCommentQuery) CursorId() (int64, error) {
+	if params.Cursor == "" {
+		return 0, nil
+	}
+	cursor, err := strconv.ParseInt(params.Cursor, 10, 64)
+	if err != nil || cursor <= 0 {
+		return 0, errors.New("cursor should be the nextCursor of the previous page")
+	}
+	return cursor, nil
+}
+
+// GetTodoHistoryQuery is the url query of GET /todos/:id/history, the
+// cursor is the nextCursor of the previous page
+type GetTodoHistoryQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+func (params *GetTodoHistoryQuery) CursorId() (int64, error) {
+	if params.Cursor == "" {
+		return 0, nil
+	}
+	cursor, err := strconv.ParseInt(params.Cursor, 10, 64)
+	if err != nil || cursor <= 0 {
+		return 0, errors.New("cursor should be the nextCursor of the previous page")
+	}
+	return cursor, nil
+}
+
+// MoveTodoBody moves the todo under the parent, null moves it to the root
+type MoveTodoBody struct {
+	ParentId *int64 `json:"parentId"`
+}
+
+// PositionTodoBody puts the todo on the status between two todos of its
+// column, previousId is the todo above and nextId the todo below
+type PositionTodoBody struct {
+	StatusID   int64  `json:"statusId"`
+	PreviousId *int64 `json:"previousId"`
+	NextId     *int64 `json:"nextId"`
+}
+
+func (body *PositionTodoBody) Validate() error {
+	if body.StatusID == 0 {
+		return errors.New("missing statusId")
+	}
+	return nil
+}
+
+// GetAllTodoQuery is the url query of GET /todos
+type GetAllTodoQuery struct {
+	BoardId       int64  `form:"boardId"`
+	StatusId      int64  `form:"statusId"`
+	Search        string `form:"q"`
+	CreatedAfter  string `form:"createdAfter"`
+	CreatedBefore string `form:"createdBefore"`
+	Due           string `form:"due"`
+	DueAfter      string `form:"dueAfter"`
+	DueBefore     string `form:"dueBefore"`
+	Timezone      string `form:"timezone"`
+	Priority      string `form:"priority"`
+	Labels        string `form:"labels"`
+	LabelMode     string `form:"labelMode"`
+	Assignee      string `form:"assignee"`
+	Sort          string `form:"sort"`
+	Direction     string `form:"direction"`
+	Cursor        string `form:"cursor"`
+	Limit         int    `form:"limit"`
+}
+
+func (params *GetAllTodoQuery) ProcessData() {
+	params.Search = strings.TrimSpace(params.Search)
+}
+
+// ToTodoQuery resolves the due filter at now, in the timezone of the user
+// informed by the timezone param (UTC by default)
+func (params *GetAllTodoQuery) ToTodoQuery(userId int64, now time.Time) (TodoQuery, error) {
+	query := TodoQuery{
+		UserId:    userId,
+		BoardId:   params.BoardId,
+		StatusId:  params.StatusId,
+		Search:    params.Search,
+		Sort:      TodoSort(params.Sort),
+		Direction: SortDirection(strings.ToLower(params.Direction)),
+		Limit:     params.Limit,
+		Now:       now,
+	}
+	if params.CreatedAfter != "" {
+		createdAfter, err := time.Parse(time.RFC3339, params.CreatedAfter)
+		if err != nil {
+			return query, errors.New("createdAfter should be a RFC3339 time")
+		}
+		query.CreatedAfter = &createdAfter
+	}
+	if params.CreatedBefore != "" {
+		createdBefore, err := time.Parse(time.RFC3339, params.CreatedBefore)
+		if err != nil {
+			return query, errors.New("createdBefore should be a RFC3339 time")
+		}
+		query.CreatedBefore = &createdBefore
+	}
+
+	location := time.UTC
+	if params.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(params.Timezone)
+		if err != nil {
+			return query, errors.New("timezone should be an IANA time zone like America/Sao_Paulo")
+		}
+	}
+	if params.Due != "" {
+		dueFrom, dueTo, err := DueFilter(params.Due).Range(now, location)
+		if err != nil {
+			return query, err
+		}
+		query.DueFrom = dueFrom
+		query.DueTo = dueTo
+		query.Open = DueFilter(params.Due) == DueOverdue
+	}
+	if params.DueAfter != "" {
+		dueAfter, err := time.Parse(time.RFC3339, params.DueAfter)
+		if err != nil {
+			return query, errors.New("dueAfter should be a RFC3339 time")
+		}
+		if query.DueFrom == nil || dueAfter.After(*query.DueFrom) {
+			query.DueFrom = &dueAfter
+		}
+	}
+	if params.DueBefore != "" {
+		dueBefore, err := time.Parse(time.RFC3339, params.DueBefore)
+		if err != nil {
+			return query, errors.New("dueBefore should be a RFC3339 time")
+		}
+		if query.DueTo == nil || dueBefore.Before(*query.DueTo) {
+			query.DueTo = &dueBefore
+		}
+	}
+
+	if params.Cursor != "" {
+		cursor, err := DecodeTodoCursor(params.Cursor)
+		if err != nil {
+			return query, err
+		}
+		query.Cursor = cursor
+		// the urgency of the next pages is computed at the time of the first one
+		if cursor.Sort == TodoSortUrgency {
+			query.Now, _ = cursor.Now()
+		}
+	}
+
+	// priority=high,urgent
+	if params.Priority != "" {
+		for _, name := range strings.Split(params.Priority, ",") {
+			priority, err := ParsePriority(strings.TrimSpace(name))
+			if err != nil {
+				return query, err
+			}
+			query.Priorities = append(query.Priorities, priority)
+		}
+	}
+
+	// labels=bug,frontend, labelMode any or all
+	if params.Labels != "" {
+		labelsAdded := map[string]bool{}
+		for _, name := range strings.Split(params.Labels, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != "" && !labelsAdded[name] {
+				labelsAdded[name] = true
+				query.Labels = append(query.Labels, name)
+			}
+		}
+		query.LabelMode = LabelMode(strings.ToLower(params.LabelMode))
+		if query.LabelMode == "" {
+			query.LabelMode = LabelModeAny
+		}
+	}
+
+	// assignee=me or the id of a member
+	if params.Assignee == "me" {
+		query.AssigneeId = userId
+	} else if params.Assignee != "" {
+		assigneeId, err := strconv.ParseInt(params.Assignee, 10, 64)
+		if err != nil || assigneeId <= 0 {
+			return query, errors.New("assignee should be me or a user id")
+		}
+		query.AssigneeId = assigneeId
+	}
+	return query, nil
+}
+
+type CreateChecklistItemBody struct {
+	Text string `json:"text"`
+}
+
+func (body *CreateChecklistItemBody) Validate() error {
+	if body.Text == "" {
+		return errors.New("missing text")
+	}
+	return nil
+}
+
+func (body *CreateChecklistItemBody) ProcessData() {
+	body.Text = strings.TrimSpace(body.Text)
+}
+
+// UpdateChecklistItemBody changes only the fields sent
+type UpdateChecklistItemBody struct {
+	Text *string `json:"text"`
+	Done *bool   `json:"done"`
+}
+
+func (body *UpdateChecklistItemBody) Validate() error {
+	if body.Text == nil && body.Done == nil {
+		return errors.New("missing text or done")
+	}
+	return nil
+}
+
+func (body *UpdateChecklistItemBody) ProcessData() {
+	if body.Text != nil {
+		text := strings.TrimSpace(*body.Text)
+		body.Text = &text
+	}
+}
+
+type ReorderChecklistBody struct {
+	ItemIds []int64 `json:"itemIds"`
+}
+
+func (body *ReorderChecklistBody) Validate() error {
+	if len(body.ItemIds) == 0 {
+		return errors.New("missing itemIds")
+	}
+	return nil
+}
+
+type CreateStatusTodoBody struct {
+	BoardId  int64          `json:"boardId"`
+	Name     string         `json:"name"`
+	Color    string         `json:"color"`
+	WipLimit *int64         `json:"wipLimit"`
+	Category StatusCategory `json:"category"`
 }
 
 func (body *CreateStatusTodoBody) Validate() error {
+	if body.BoardId == 0 {
+		return errors.New("missing boardId")
+	}
 	if body.Name == "" {
 		return errors.New("missing name")
 	}
@@ -62,6 +453,59 @@ func (body *CreateStatusTodoBody) Validate() error {
 
 func (body *CreateStatusTodoBody) ProcessData() {
 	body.Name = strings.TrimSpace(body.Name)
+	body.Color = strings.ToLower(strings.TrimSpace(body.Color))
+	if body.Color == "" {
+		body.Color = StatusTodoDefaultColor
+	}
+	if body.Category == "" {
+		body.Category = StatusCategoryTodo
+	}
+}
+
+type UpdateStatusTodoBody struct {
+	Name     string         `json:"name"`
+	Color    string         `json:"color"`
+	WipLimit *int64         `json:"wipLimit"`
+	Category StatusCategory `json:"category"`
+}
+
+func (body *UpdateStatusTodoBody) Validate() error {
+	if body.Name == "" {
+		return errors.New("missing name")
+	}
+	return nil
+}
+
+func (body *UpdateStatusTodoBody) ProcessData() {
+	body.Name = strings.TrimSpace(body.Name)
+	body.Color = strings.ToLower(strings.TrimSpace(body.Color))
+	if body.Color == "" {
+		body.Color = StatusTodoDefaultColor
+	}
+	if body.Category == "" {
+		body.Category = StatusCategoryTodo
+	}
+}
+
+// StatusTransitionsBody replaces the statuses allowed after a status,
+// empty allows every status
+type StatusTransitionsBody struct {
+	ToStatusIds []int64 `json:"toStatusIds"`
+}
+
+type ReorderStatusTodoBody struct {
+	BoardId   int64   `json:"boardId"`
+	StatusIds []int64 `json:"statusIds"`
+}
+
+func (body *ReorderStatusTodoBody) Validate() error {
+	if body.BoardId == 0 {
+		return errors.New("missing boardId")
+	}
+	if len(body.StatusIds) == 0 {
+		return errors.New("missing statusIds")
+	}
+	return nil
 }
 
 type UpdateImageTodoDTO struct {
@@ -0,0 +1,70 @@
+package todos
+
+import (
+	"api/modules/users/middlewares"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HistoryController interface {
+	GetTodoHistory() func(c *gin.Context)
+}
+
+type HistoryControllerGin struct {
+	historyUsecase HistoryUsecase
+}
+
+func NewHistoryController(historyUsecase HistoryUsecase) HistoryController {
+	return &HistoryControllerGin{historyUsecase}
+}
+
+func (controller *HistoryControllerGin) GetTodoHistory() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		todoIdStr, hasTodoId := c.Params.Get("id")
+		if !hasTodoId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id on url param"})
+			return
+		}
+		todoId, err := strconv.ParseInt(todoIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing todo id integer on url param"})
+			return
+		}
+
+		var params GetTodoHistoryQuery
+		if err := c.ShouldBindQuery(&params); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "invalid query params"})
+			return
+		}
+		cursor, err := params.CursorId()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for get todo history")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		page, usecaseErr, serverErr := controller.historyUsecase.GetTodoHistory(userId, todoId, cursor, params.Limit)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, page.ToDtoHttpResponse())
+	}
+}
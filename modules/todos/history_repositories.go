@@ -0,0 +1,151 @@
+package todos
+
+import (
+	usersModels "api/modules/users/models"
+	"database/sql"
+)
+
+type HistoryRepository interface {
+	GetAllTodoEvent(todoId, afterId int64, limit int) ([]*TodoEvent, error)
+	CountTodoEvent(todoId int64) (int64, error)
+}
+
+type HistoryRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewHistoryRepository(db *sql.DB) HistoryRepository {
+	return &HistoryRepositoryPG{db}
+}
+
+// insertTodoEvent adds the event in the transaction of the change, the
+// history never has a change that was rolled back
+func insertTodoEvent(tx *sql.Tx, todoId, boardId, actorId int64, action TodoEventAction, changes TodoChanges) error {
+	sqlInsert := `
+		INSERT INTO todos.todo_event (todo_id, board_id, actor_id, action, changes)
+		VALUES ($1, $2, $3, $4, $5);
+	`
+	_, err := tx.Exec(sqlInsert, todoId, boardId, actorId, action, changes)
+	return err
+}
+
+// insertTodoChange adds the change of the todo from before to after, a nil
+// before is a created todo and a nil after a deleted one. Updates without
+// changes are not recorded, a new image always is.
+func insertTodoChange(tx *sql.Tx, actorId int64, action TodoEventAction, before, after *Todo) error {
+	changes := DiffTodo(before, after)
+	if len(changes) == 0 && action != TodoEventImageUpdated {
+		return nil
+	}
+	todo := after
+	if todo == nil {
+		todo = before
+	}
+	return insertTodoEvent(tx, todo.ID, todo.BoardId, actorId, action, changes)
+}
+
+// todoEventColumns are the fields of the history returned by a change of
+// todos.todo t joined to its status ts
+const todoEventColumns = `t.id, t.title, t.description, t.tstts_id, t.start_at, t.due_at, t.priority, t.parent_id, t.position, t.completed_at, ts.board_id, t.image IS NOT NULL`
+
+// scanTodoEventRows reads the todoEventColumns of the rows and closes them
+func scanTodoEventRows(rows *sql.Rows) ([]*Todo, error) {
+	defer rows.Close()
+	todos := make([]*Todo, 0)
+	for rows.Next() {
+		var todo Todo
+		err := rows.Scan(
+			&todo.ID,
+			&todo.Title,
+			&todo.Description,
+			&todo.StatusID,
+			&todo.StartAt,
+			&todo.DueAt,
+			&todo.Priority,
+			&todo.ParentId,
+			&todo.Position,
+			&todo.CompletedAt,
+			&todo.BoardId,
+			&todo.HasImage,
+		)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, &todo)
+	}
+	return todos, rows.Err()
+}
+
+// GetAllTodoEvent returns the events after afterId from the oldest, from
+// the first one when afterId is 0
+func (repo *HistoryRepositoryPG) GetAllTodoEvent(todoId, afterId int64, limit int) ([]*TodoEvent, error) {
+	var events = make([]*TodoEvent, 0)
+	sqlGet := `
+		SELECT e.id, e.todo_id, e.board_id, e.actor_id, e.action, e.changes, e.created_at,
+			u.id, u.name, u.username, CASE WHEN u.photo IS NULL THEN '' ELSE '/users/photo/' || u.id END
+		FROM todos.todo_event e
+		LEFT JOIN users.user u ON u.id = e.actor_id
+		WHERE 
+			e.todo_id=$1 AND
+			e.id > $2
+		ORDER BY e.id
+		LIMIT $3;
+	`
+	rows, err := repo.db.Query(sqlGet, todoId, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event TodoEvent
+		var actorId, actorUserId sql.NullInt64
+		var actorName, actorUsername, actorPhotoUrl sql.NullString
+		err := rows.Scan(
+			&event.ID,
+			&event.TodoId,
+			&event.BoardId,
+			&actorId,
+			&event.Action,
+			&event.Changes,
+			&event.CreatedAt,
+			&actorUserId,
+			&actorName,
+			&actorUsername,
+			&actorPhotoUrl,
+		)
+		if err != nil {
+			return nil, err
+		}
+		event.ActorId = actorId.Int64
+		// the actor is gone after its account is deleted
+		if actorUserId.Valid {
+			event.Actor = &usersModels.UserSafeHttp{
+				ID:       actorUserId.Int64,
+				Name:     actorName.String,
+				Username: actorUsername.String,
+				PhotoUrl: actorPhotoUrl.String,
+			}
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+func (repo *HistoryRepositoryPG) CountTodoEvent(todoId int64) (int64, error) {
+	var count int64
+	sqlCount := `
+		SELECT COUNT(*)
+		FROM todos.todo_event
+		WHERE todo_id=$1
+	`
+	row := repo.db.QueryRow(sqlCount, todoId)
+	if row.Err() != nil {
+		return -1, row.Err()
+	}
+	err := row.Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+	return count, nil
+}
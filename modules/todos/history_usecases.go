@@ -0,0 +1,67 @@
+package todos
+
+import (
+	"errors"
+)
+
+type HistoryUsecase interface {
+	GetTodoHistory(userId, todoId, cursor int64, limit int) (page *TodoEventPage, usecaseErr error, serverErr error)
+}
+
+var (
+	ErrTodoEventLimitInvalid = errors.New("limit should be between 1 and 200")
+)
+
+type DBHistoryUsecase struct {
+	historyRepository HistoryRepository
+	todoRepository    TodoRepository
+}
+
+func NewHistoryUsecase(historyRepository HistoryRepository, todoRepository TodoRepository) HistoryUsecase {
+	return &DBHistoryUsecase{historyRepository, todoRepository}
+}
+
+// GetTodoHistory returns the events after the cursor, the id of the last
+// event of the previous page
+func (usecase *DBHistoryUsecase) GetTodoHistory(userId, todoId, cursor int64, limit int) (page *TodoEventPage, usecaseErr error, serverErr error) {
+	if limit == 0 {
+		limit = TodoEventDefaultLimit
+	}
+	if limit < 1 || limit > TodoEventMaxLimit {
+		usecaseErr = ErrTodoEventLimitInvalid
+		return
+	}
+	if todoId <= 0 {
+		usecaseErr = ErrTodoIdIsNegative
+		return
+	}
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	todo, serverErr := usecase.todoRepository.GetTodo(userId, todoId)
+	if serverErr != nil {
+		return
+	}
+	if todo == nil {
+		usecaseErr = ErrTodoNotFound
+		return
+	}
+
+	// one more event says there is a next page
+	events, serverErr := usecase.historyRepository.GetAllTodoEvent(todoId, cursor, limit+1)
+	if serverErr != nil {
+		return
+	}
+	total, serverErr := usecase.historyRepository.CountTodoEvent(todoId)
+	if serverErr != nil {
+		return
+	}
+	page = &TodoEventPage{Events: events, Total: total}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		nextCursor := page.Events[limit-1].ID
+		page.NextCursor = &nextCursor
+	}
+	return
+}
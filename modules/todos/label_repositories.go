@@ -0,0 +1,216 @@
+package todos
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type LabelRepository interface {
+	InsertLabel(userId int64, name, color string) (*Label, error)
+	UpdateLabel(userId, labelId int64, name, color string) error
+	DeleteLabel(userId, labelId int64) error
+	GetLabel(userId, labelId int64) (*Label, error)
+	GetLabelByName(userId int64, name string) (*Label, error)
+	GetAllLabel(userId int64) ([]*Label, error)
+
+	AddTodoLabel(todoId, labelId int64) error
+	RemoveTodoLabel(todoId, labelId int64) (bool, error)
+}
+
+type LabelRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewLabelRepository(db *sql.DB) LabelRepository {
+	return &LabelRepositoryPG{db}
+}
+
+// labelNameErr returns ErrLabelAlreadyExists when the name is taken by a
+// concurrent create or rename on the unique index of the names
+func labelNameErr(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrLabelAlreadyExists
+	}
+	return err
+}
+
+func (repo *LabelRepositoryPG) InsertLabel(userId int64, name, color string) (*Label, error) {
+	var label Label
+	sqlInsert := `
+		INSERT INTO todos.label (user_id, name, color)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at;
+	`
+	args := []interface{}{userId, name, color}
+	row := repo.db.QueryRow(sqlInsert, args...)
+	if row.Err() != nil {
+		return nil, labelNameErr(row.Err())
+	}
+	err := row.Scan(&label.ID, &label.CreatedAt, &label.UpdatedAt)
+	if err != nil {
+		return nil, labelNameErr(err)
+	}
+	label.UserId = userId
+	label.Name = name
+	label.Color = color
+	return &label, nil
+}
+
+// UpdateLabel renames the label of every todo too, they only keep its id
+func (repo *LabelRepositoryPG) UpdateLabel(userId, labelId int64, name, color string) error {
+	now := time.Now().UTC()
+	sqlUpdate := `
+		UPDATE todos.label
+		SET 
+			name=$3,
+			color=$4,
+			updated_at=$5
+		WHERE 
+			id=$2 AND
+			user_id=$1;
+	`
+	args := []interface{}{userId, labelId, name, color, now}
+	_, err := repo.db.Exec(sqlUpdate, args...)
+	return labelNameErr(err)
+}
+
+// DeleteLabel removes the label from every todo by the cascade of todo_label
+func (repo *LabelRepositoryPG) DeleteLabel(userId, labelId int64) error {
+	sqlDelete := `
+		DELETE FROM todos.label
+		WHERE 
+			id=$2 AND
+			user_id=$1;
+	`
+	_, err := repo.db.Exec(sqlDelete, userId, labelId)
+	return err
+}
+
+func (repo *LabelRepositoryPG) GetLabel(userId, labelId int64) (*Label, error) {
+	var label Label
+	sqlGet := `
+		SELECT id, user_id, name, color, created_at, updated_at
+		FROM todos.label
+		WHERE 
+			id=$2 AND
+			user_id=$1;
+	`
+	row := repo.db.QueryRow(sqlGet, userId, labelId)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err := row.Scan(
+		&label.ID,
+		&label.UserId,
+		&label.Name,
+		&label.Color,
+		&label.CreatedAt,
+		&label.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &label, nil
+}
+
+func (repo *LabelRepositoryPG) GetLabelByName(userId int64, name string) (*Label, error) {
+	var label Label
+	sqlGet := `
+		SELECT id, user_id, name, color, created_at, updated_at
+		FROM todos.label
+		WHERE 
+			LOWER(name)=$1 AND
+			user_id=$2;
+	`
+
+	nameLower := strings.ToLower(name)
+	row := repo.db.QueryRow(sqlGet, nameLower, userId)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err := row.Scan(
+		&label.ID,
+		&label.UserId,
+		&label.Name,
+		&label.Color,
+		&label.CreatedAt,
+		&label.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &label, nil
+}
+
+func (repo *LabelRepositoryPG) GetAllLabel(userId int64) ([]*Label, error) {
+	var labels = make([]*Label, 0)
+	sqlGet := `
+		SELECT id, user_id, name, color, created_at, updated_at
+		FROM todos.label
+		WHERE user_id=$1
+		ORDER BY LOWER(name), id;
+	`
+	rows, err := repo.db.Query(sqlGet, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var label Label
+		err := rows.Scan(
+			&label.ID,
+			&label.UserId,
+			&label.Name,
+			&label.Color,
+			&label.CreatedAt,
+			&label.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, &label)
+	}
+	return labels, rows.Err()
+}
+
+// AddTodoLabel does nothing when the todo already has the label
+func (repo *LabelRepositoryPG) AddTodoLabel(todoId, labelId int64) error {
+	sqlInsert := `
+		INSERT INTO todos.todo_label (todo_id, label_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`
+	_, err := repo.db.Exec(sqlInsert, todoId, labelId)
+	return err
+}
+
+// RemoveTodoLabel returns false when the todo didn't have the label
+func (repo *LabelRepositoryPG) RemoveTodoLabel(todoId, labelId int64) (bool, error) {
+	sqlDelete := `
+		DELETE FROM todos.todo_label
+		WHERE 
+			todo_id=$1 AND
+			label_id=$2;
+	`
+	result, err := repo.db.Exec(sqlDelete, todoId, labelId)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,203 @@
+package todos
+
+import (
+	"errors"
+)
+
+type LabelUsecase interface {
+	CreateLabel(userId int64, name, color string) (label *Label, usecaseErr error, serverErr error)
+	UpdateLabel(userId, labelId int64, name, color string) (label *Label, usecaseErr error, serverErr error)
+	DeleteLabel(userId, labelId int64) (usecaseErr error, serverErr error)
+	GetLabel(userId, labelId int64) (label *Label, usecaseErr error, serverErr error)
+	GetAllLabel(userId int64) (labels []*Label, usecaseErr error, serverErr error)
+
+	AddTodoLabel(userId, todoId, labelId int64) (usecaseErr error, serverErr error)
+	RemoveTodoLabel(userId, todoId, labelId int64) (usecaseErr error, serverErr error)
+}
+
+var (
+	ErrLabelNotFound      = errors.New("label not found")
+	ErrLabelNameInvalid   = errors.New("label name should have between 1 and 50 characters")
+	ErrLabelAlreadyExists = errors.New("label already exists")
+	ErrLabelIdNegative    = errors.New("label id should be positive")
+)
+
+type DBLabelUsecase struct {
+	labelRepository LabelRepository
+	todoRepository  TodoRepository
+}
+
+func NewLabelUsecase(
+	labelRepository LabelRepository,
+	todoRepository TodoRepository,
+) LabelUsecase {
+	return &DBLabelUsecase{labelRepository, todoRepository}
+}
+
+func validLabel(name, color string) error {
+	if len(name) < 1 || len(name) > 50 {
+		return ErrLabelNameInvalid
+	}
+	return ValidColor(color)
+}
+
+func (usecase *DBLabelUsecase) CreateLabel(userId int64, name, color string) (label *Label, usecaseErr error, serverErr error) {
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	usecaseErr = validLabel(name, color)
+	if usecaseErr != nil {
+		return
+	}
+
+	// names are unique without case, like the status names
+	labelFound, err := usecase.labelRepository.GetLabelByName(userId, name)
+	if err != nil {
+		serverErr = err
+		return
+	}
+	if labelFound != nil {
+		usecaseErr = ErrLabelAlreadyExists
+		return
+	}
+
+	label, serverErr = usecase.labelRepository.InsertLabel(userId, name, color)
+	if errors.Is(serverErr, ErrLabelAlreadyExists) {
+		usecaseErr, serverErr = serverErr, nil
+	}
+	return
+}
+
+func (usecase *DBLabelUsecase) UpdateLabel(userId, labelId int64, name, color string) (label *Label, usecaseErr error, serverErr error) {
+	usecaseErr = validLabel(name, color)
+	if usecaseErr != nil {
+		return
+	}
+	label, usecaseErr, serverErr = usecase.GetLabel(userId, labelId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	labelFoundByName, err := usecase.labelRepository.GetLabelByName(userId, name)
+	if err != nil {
+		serverErr = err
+		return
+	}
+	if labelFoundByName != nil && labelFoundByName.ID != labelId {
+		usecaseErr = ErrLabelAlreadyExists
+		return
+	}
+
+	serverErr = usecase.labelRepository.UpdateLabel(userId, labelId, name, color)
+	if errors.Is(serverErr, ErrLabelAlreadyExists) {
+		label = nil
+		usecaseErr, serverErr = serverErr, nil
+		return
+	}
+	if serverErr != nil {
+		return
+	}
+	label, serverErr = usecase.labelRepository.GetLabel(userId, labelId)
+	return
+}
+
+func (usecase *DBLabelUsecase) DeleteLabel(userId, labelId int64) (usecaseErr error, serverErr error) {
+	_, usecaseErr, serverErr = usecase.GetLabel(userId, labelId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	serverErr = usecase.labelRepository.DeleteLabel(userId, labelId)
+	return
+}
+
+func (usecase *DBLabelUsecase) GetLabel(userId, labelId int64) (label *Label, usecaseErr error, serverErr error) {
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	if labelId <= 0 {
+		usecaseErr = ErrLabelIdNegative
+		return
+	}
+	label, serverErr = usecase.labelRepository.GetLabel(userId, labelId)
+	if serverErr != nil {
+		return
+	}
+	if label == nil {
+		usecaseErr = ErrLabelNotFound
+	}
+	return
+}
+
+func (usecase *DBLabelUsecase) GetAllLabel(userId int64) (labels []*Label, usecaseErr error, serverErr error) {
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	labels, serverErr = usecase.labelRepository.GetAllLabel(userId)
+	return
+}
+
+// checkTodoLabel makes sure the label belongs to the user and the todo is
+// on a board the user changes
+func (usecase *DBLabelUsecase) checkTodoLabel(userId, todoId, labelId int64) (usecaseErr error, serverErr error) {
+	if todoId <= 0 {
+		usecaseErr = ErrTodoIdIsNegative
+		return
+	}
+	_, usecaseErr, serverErr = usecase.GetLabel(userId, labelId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	return usecase.checkTodo(userId, todoId)
+}
+
+// checkTodo makes sure the todo is on a board the user changes
+func (usecase *DBLabelUsecase) checkTodo(userId, todoId int64) (usecaseErr error, serverErr error) {
+	if todoId <= 0 {
+		usecaseErr = ErrTodoIdIsNegative
+		return
+	}
+	todoFound, serverErr := usecase.todoRepository.GetTodo(userId, todoId)
+	if serverErr != nil {
+		return
+	}
+	if todoFound == nil {
+		usecaseErr = ErrTodoNotFound
+		return
+	}
+	if !todoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+	}
+	return
+}
+
+func (usecase *DBLabelUsecase) AddTodoLabel(userId, todoId, labelId int64) (usecaseErr error, serverErr error) {
+	usecaseErr, serverErr = usecase.checkTodoLabel(userId, todoId, labelId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	serverErr = usecase.labelRepository.AddTodoLabel(todoId, labelId)
+	return
+}
+
+// RemoveTodoLabel removes any label on the todo, the labels attached by the
+// other members of the board too
+func (usecase *DBLabelUsecase) RemoveTodoLabel(userId, todoId, labelId int64) (usecaseErr error, serverErr error) {
+	if labelId <= 0 {
+		usecaseErr = ErrLabelIdNegative
+		return
+	}
+	usecaseErr, serverErr = usecase.checkTodo(userId, todoId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	removed, serverErr := usecase.labelRepository.RemoveTodoLabel(todoId, labelId)
+	if serverErr != nil || removed {
+		return
+	}
+	// a label of the user not on the todo is already removed
+	_, usecaseErr, serverErr = usecase.GetLabel(userId, labelId)
+	return
+}
@@ -1,8 +1,16 @@
 package todos
 
 import (
+	usersModels "api/modules/users/models"
 	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,33 +21,871 @@ type Todo struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	StatusID    int64
-	Image       bytes.Buffer
+	StartAt     *time.Time
+	DueAt       *time.Time
+	Priority    Priority
+	ParentId    *int64
+	// Position is the rank of the todo on its status column
+	Position string
+	// CompletedAt is when the todo entered a done status
+	CompletedAt *time.Time
+	// BoardId is the board of the status of the todo
+	BoardId int64
+	// Role is the role on the board of the user who got the todo
+	Role  BoardRole
+	Image bytes.Buffer
+	// HasImage is set by the list queries, they don't load the image
+	HasImage bool
+	// Urgency is set by the list sorted by urgency
+	Urgency *float64
+	// checklist items done and total
+	ChecklistDone  int64
+	ChecklistTotal int64
+	Labels         LabelList
+	Assignees      UserList
+	CommentCount   int64
 }
 
 func (t *Todo) ToDtoHttpResponse() *TodoDtoHttpResponse {
 	var imageUrl string
-	if t.Image.Len() > 0 {
+	if t.HasImage || t.Image.Len() > 0 {
 		imageUrl = fmt.Sprintf("/todos/image/%d", t.ID)
 	}
 	return &TodoDtoHttpResponse{
-		t.ID, t.Title, t.Description, t.CreatedAt, t.UpdatedAt, t.StatusID, imageUrl,
+		t.ID, t.Title, t.Description, t.CreatedAt, t.UpdatedAt, t.StatusID, imageUrl, t.StartAt, t.DueAt, t.Priority, t.Urgency,
+		fmt.Sprintf("%d/%d", t.ChecklistDone, t.ChecklistTotal),
+		t.ParentId,
+		t.Labels.ToDtoHttpResponse(),
+		t.Position,
+		t.CompletedAt,
+		t.BoardId,
+		t.Assignees.ToDtoHttpResponse(),
+		t.CommentCount,
 	}
 }
 
 type TodoDtoHttpResponse struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
-	StatusID    int64     `json:"statusId"`
-	ImageUrl    string    `json:"imageUrl"`
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	StatusID    int64      `json:"statusId"`
+	ImageUrl    string     `json:"imageUrl"`
+	StartAt     *time.Time `json:"startAt"`
+	DueAt       *time.Time `json:"dueAt"`
+	Priority    Priority   `json:"priority"`
+	Urgency     *float64   `json:"urgency,omitempty"`
+	// ChecklistProgress is like 3/5, items done of the total
+	ChecklistProgress string                  `json:"checklistProgress"`
+	ParentId          *int64                  `json:"parentId"`
+	Labels            []*LabelDtoHttpResponse `json:"labels"`
+	Position          string                  `json:"position"`
+	CompletedAt       *time.Time              `json:"completedAt"`
+	BoardId           int64                   `json:"boardId"`
+	Assignees         []*UserDtoHttpResponse  `json:"assignees"`
+	CommentCount      int64                   `json:"commentCount"`
 }
 
-type StatusTodo struct {
+// todoMaxDepth limits the recursive queries of the todo tree
+const todoMaxDepth = 32
+
+// ChildrenDeleteMode is what happens to the children of a deleted todo
+type ChildrenDeleteMode string
+
+const (
+	// ChildrenDeleteCascade deletes the whole subtree
+	ChildrenDeleteCascade ChildrenDeleteMode = "cascade"
+	// ChildrenDeleteReparent moves the children to the parent of the deleted todo
+	ChildrenDeleteReparent ChildrenDeleteMode = "reparent"
+)
+
+var (
+	ErrPriorityInvalid = errors.New("priority should be none, low, medium, high or urgent")
+)
+
+// Priority is stored as its level and sent as its name
+type Priority int16
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+func ParsePriority(name string) (Priority, error) {
+	for level, priorityName := range priorityNames {
+		if strings.EqualFold(name, priorityName) {
+			return Priority(level), nil
+		}
+	}
+	return PriorityNone, ErrPriorityInvalid
+}
+
+func (p Priority) Valid() error {
+	if p < PriorityNone || p > PriorityUrgent {
+		return ErrPriorityInvalid
+	}
+	return nil
+}
+
+func (p Priority) String() string {
+	if p.Valid() != nil {
+		return strconv.Itoa(int(p))
+	}
+	return priorityNames[p]
+}
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var name string
+	err := json.Unmarshal(data, &name)
+	if err != nil {
+		return ErrPriorityInvalid
+	}
+	priority, err := ParsePriority(name)
+	if err != nil {
+		return err
+	}
+	*p = priority
+	return nil
+}
+
+type ChecklistItem struct {
 	ID        int64     `json:"id"`
+	TodoId    int64     `json:"todoId"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	Position  int64     `json:"position"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+var (
+	ErrColorInvalid     = errors.New("color should be like #1e90ff")
+	ErrLabelModeInvalid = errors.New("labelMode should be any or all")
+)
+
+var colorRegexp = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+type Label struct {
+	ID        int64     `json:"id"`
+	UserId    int64     `json:"userId"`
 	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func ValidColor(color string) error {
+	if !colorRegexp.MatchString(color) {
+		return ErrColorInvalid
+	}
+	return nil
+}
+
+func (l *Label) ToDtoHttpResponse() *LabelDtoHttpResponse {
+	return &LabelDtoHttpResponse{l.ID, l.Name, l.Color}
+}
+
+// LabelDtoHttpResponse is the label embedded in the todo
+type LabelDtoHttpResponse struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// LabelList is scanned from the json array aggregated by the todo queries
+type LabelList []*Label
+
+func (labels *LabelList) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*labels = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, labels)
+	case string:
+		return json.Unmarshal([]byte(value), labels)
+	}
+	return fmt.Errorf("can't scan %T into labels", src)
+}
+
+func (labels LabelList) ToDtoHttpResponse() []*LabelDtoHttpResponse {
+	labelsDto := make([]*LabelDtoHttpResponse, 0, len(labels))
+	for _, label := range labels {
+		labelsDto = append(labelsDto, label.ToDtoHttpResponse())
+	}
+	return labelsDto
+}
+
+// UserList is scanned from the json arrays of users aggregated by the
+// queries, the users only have the fields of UserDtoHttpResponse
+type UserList []*usersModels.UserSafeHttp
+
+func (users *UserList) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*users = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, users)
+	case string:
+		return json.Unmarshal([]byte(value), users)
+	}
+	return fmt.Errorf("can't scan %T into users", src)
+}
+
+func (users UserList) ToDtoHttpResponse() []*UserDtoHttpResponse {
+	usersDto := make([]*UserDtoHttpResponse, 0, len(users))
+	for _, user := range users {
+		usersDto = append(usersDto, NewUserDtoHttpResponse(user))
+	}
+	return usersDto
+}
+
+func NewUserDtoHttpResponse(user *usersModels.UserSafeHttp) *UserDtoHttpResponse {
+	return &UserDtoHttpResponse{user.ID, user.Name, user.Username, user.PhotoUrl}
+}
+
+// UserDtoHttpResponse is the user embedded in the todos and the comments
+type UserDtoHttpResponse struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	PhotoUrl string `json:"photoUrl"`
+}
+
+// Comment is a message of the thread of the todo, its mentions are the
+// users so they resolve after a rename
+type Comment struct {
+	ID        int64
+	TodoId    int64
+	Author    usersModels.UserSafeHttp
+	Body      string
+	Mentions  UserList
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (comment *Comment) ToDtoHttpResponse() *CommentDtoHttpResponse {
+	return &CommentDtoHttpResponse{
+		comment.ID,
+		comment.TodoId,
+		NewUserDtoHttpResponse(&comment.Author),
+		comment.Body,
+		comment.Mentions.ToDtoHttpResponse(),
+		comment.CreatedAt,
+		comment.UpdatedAt,
+	}
+}
+
+type CommentDtoHttpResponse struct {
+	ID        int64                  `json:"id"`
+	TodoId    int64                  `json:"todoId"`
+	Author    *UserDtoHttpResponse   `json:"author"`
+	Body      string                 `json:"body"`
+	Mentions  []*UserDtoHttpResponse `json:"mentions"`
+	CreatedAt time.Time              `json:"createdAt"`
+	UpdatedAt time.Time              `json:"updatedAt"`
+}
+
+const (
+	CommentDefaultLimit = 20
+	CommentMaxLimit     = 100
+	// commentMaxMentions bounds the usernames looked up for a comment
+	commentMaxMentions = 20
+)
+
+// mentionRegexp finds the @username of a comment body
+var mentionRegexp = regexp.MustCompile(`(^|[^\w@])@([\w.-]+)`)
+
+// ParseMentions returns the usernames mentioned on the body once each,
+// in the order they appear
+func ParseMentions(body string) []string {
+	usernames := make([]string, 0)
+	usernamesAdded := map[string]bool{}
+	for _, match := range mentionRegexp.FindAllStringSubmatch(body, -1) {
+		// a mention at the end of a sentence
+		username := strings.TrimRight(match[2], ".")
+		if username != "" && !usernamesAdded[username] {
+			usernamesAdded[username] = true
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
+}
+
+// CommentPage is a page of the thread from the oldest comment, NextCursor
+// is the id of the last comment when there are more
+type CommentPage struct {
+	Comments   []*Comment
+	NextCursor *int64
+	Total      int64
+}
+
+func (page *CommentPage) ToDtoHttpResponse() *CommentPageDtoHttpResponse {
+	response := &CommentPageDtoHttpResponse{
+		Comments: make([]*CommentDtoHttpResponse, 0, len(page.Comments)),
+		Total:    page.Total,
+	}
+	for _, comment := range page.Comments {
+		response.Comments = append(response.Comments, comment.ToDtoHttpResponse())
+	}
+	if page.NextCursor != nil {
+		response.NextCursor = strconv.FormatInt(*page.NextCursor, 10)
+	}
+	return response
+}
+
+type CommentPageDtoHttpResponse struct {
+	Comments   []*CommentDtoHttpResponse `json:"comments"`
+	NextCursor string                    `json:"nextCursor"`
+	Total      int64                     `json:"total"`
+}
+
+// TodoEventAction is the change recorded by a todo event
+type TodoEventAction string
+
+const (
+	TodoEventCreated      TodoEventAction = "created"
+	TodoEventUpdated      TodoEventAction = "updated"
+	TodoEventMoved        TodoEventAction = "moved"
+	TodoEventImageUpdated TodoEventAction = "image-updated"
+	TodoEventImageDeleted TodoEventAction = "image-deleted"
+	TodoEventDeleted      TodoEventAction = "deleted"
+	TodoEventRestored     TodoEventAction = "restored"
+	TodoEventAssigned     TodoEventAction = "assigned"
+	TodoEventUnassigned   TodoEventAction = "unassigned"
+)
+
+// TodoEvent is an immutable entry of the history of the todo, the actor
+// is nil after its account is deleted
+type TodoEvent struct {
+	ID        int64
+	TodoId    int64
+	BoardId   int64
+	ActorId   int64
+	Actor     *usersModels.UserSafeHttp
+	Action    TodoEventAction
+	Changes   TodoChanges
+	CreatedAt time.Time
+}
+
+func (event *TodoEvent) ToDtoHttpResponse() *TodoEventDtoHttpResponse {
+	var actor *UserDtoHttpResponse
+	if event.Actor != nil {
+		actor = NewUserDtoHttpResponse(event.Actor)
+	}
+	return &TodoEventDtoHttpResponse{event.ID, event.TodoId, actor, event.Action, event.Changes, event.CreatedAt}
+}
+
+type TodoEventDtoHttpResponse struct {
+	ID        int64                `json:"id"`
+	TodoId    int64                `json:"todoId"`
+	Actor     *UserDtoHttpResponse `json:"actor"`
+	Action    TodoEventAction      `json:"action"`
+	Changes   TodoChanges          `json:"changes"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+// TodoFieldChange is a field before and after the event, nil when the
+// field had no value
+type TodoFieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// TodoChanges are the changed fields by their name on TodoDtoHttpResponse
+type TodoChanges map[string]*TodoFieldChange
+
+func (changes *TodoChanges) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*changes = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, changes)
+	case string:
+		return json.Unmarshal([]byte(value), changes)
+	}
+	return fmt.Errorf("can't scan %T into changes", src)
+}
+
+func (changes TodoChanges) Value() (driver.Value, error) {
+	if changes == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(changes)
+}
+
+// DiffTodo returns the fields changed from before to after, a nil todo
+// has no fields so the todo created or deleted has all of them
+func DiffTodo(before, after *Todo) TodoChanges {
+	changes := TodoChanges{}
+	fieldsBefore := todoEventFields(before)
+	fieldsAfter := todoEventFields(after)
+	for name := range todoEventFields(&Todo{}) {
+		if fieldsBefore[name] != fieldsAfter[name] {
+			changes[name] = &TodoFieldChange{fieldsBefore[name], fieldsAfter[name]}
+		}
+	}
+	return changes
+}
+
+// todoEventFields has comparable values, a missing value is nil
+func todoEventFields(todo *Todo) map[string]interface{} {
+	if todo == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"title":       todo.Title,
+		"description": todo.Description,
+		"statusId":    todo.StatusID,
+		"startAt":     todoEventTime(todo.StartAt),
+		"dueAt":       todoEventTime(todo.DueAt),
+		"priority":    todo.Priority.String(),
+		"parentId":    todoEventId(todo.ParentId),
+		"position":    todo.Position,
+		"completedAt": todoEventTime(todo.CompletedAt),
+		"hasImage":    todo.HasImage || todo.Image.Len() > 0,
+	}
+}
+
+func todoEventTime(value *time.Time) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339Nano)
+}
+
+func todoEventId(value *int64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+const (
+	TodoEventDefaultLimit = 50
+	TodoEventMaxLimit     = 200
+)
+
+// TodoEventPage is a page of the history from the oldest event, NextCursor
+// is the id of the last event when there are more
+type TodoEventPage struct {
+	Events     []*TodoEvent
+	NextCursor *int64
+	Total      int64
+}
+
+func (page *TodoEventPage) ToDtoHttpResponse() *TodoEventPageDtoHttpResponse {
+	response := &TodoEventPageDtoHttpResponse{
+		Events: make([]*TodoEventDtoHttpResponse, 0, len(page.Events)),
+		Total:  page.Total,
+	}
+	for _, event := range page.Events {
+		response.Events = append(response.Events, event.ToDtoHttpResponse())
+	}
+	if page.NextCursor != nil {
+		response.NextCursor = strconv.FormatInt(*page.NextCursor, 10)
+	}
+	return response
+}
+
+type TodoEventPageDtoHttpResponse struct {
+	Events     []*TodoEventDtoHttpResponse `json:"events"`
+	NextCursor string                      `json:"nextCursor"`
+	Total      int64                       `json:"total"`
+}
+
+// LabelMode is how the todos are filtered by many labels
+type LabelMode string
+
+const (
+	// LabelModeAny keeps the todos with at least one of the labels
+	LabelModeAny LabelMode = "any"
+	// LabelModeAll keeps the todos with every label
+	LabelModeAll LabelMode = "all"
+)
+
+// Board owns an ordered set of statuses and the todos on them, its
+// members see it with their role. UserId is the user who created it, 0
+// after the account was deleted.
+type Board struct {
+	ID     int64  `json:"id"`
+	UserId int64  `json:"userId"`
+	Name   string `json:"name"`
+	// Role is the role of the user who got the board
+	Role      BoardRole `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Statuses are the columns in order, only set by the board get
+	Statuses []*StatusTodo `json:"statuses,omitempty"`
+}
+
+var (
+	ErrBoardRoleInvalid = errors.New("role should be viewer, editor or owner")
+)
+
+// BoardRole is what a member can do on the board
+type BoardRole string
+
+const (
+	// BoardRoleViewer only reads the board
+	BoardRoleViewer BoardRole = "viewer"
+	// BoardRoleEditor changes the todos and the statuses
+	BoardRoleEditor BoardRole = "editor"
+	// BoardRoleOwner also changes the board and its members
+	BoardRoleOwner BoardRole = "owner"
+)
+
+func (role BoardRole) Valid() error {
+	switch role {
+	case BoardRoleViewer, BoardRoleEditor, BoardRoleOwner:
+		return nil
+	}
+	return ErrBoardRoleInvalid
+}
+
+func (role BoardRole) CanWrite() bool {
+	return role == BoardRoleEditor || role == BoardRoleOwner
+}
+
+// BoardMember is a user with access to the board
+type BoardMember struct {
+	BoardId   int64     `json:"boardId"`
 	UserId    int64     `json:"userId"`
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	Role      BoardRole `json:"role"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
+
+// defaultBoardStatuses seed a new board when asked
+var defaultBoardStatuses = []struct {
+	Name     string
+	Category StatusCategory
+}{
+	{"To do", StatusCategoryTodo},
+	{"Doing", StatusCategoryInProgress},
+	{"Done", StatusCategoryDone},
+}
+
+// StatusTodoDefaultColor is the color of a status created without one
+const StatusTodoDefaultColor = "#9e9e9e"
+
+// StatusTodo is a column of a board, UserId is the user who created it, 0
+// after the account was deleted
+type StatusTodo struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	UserId  int64  `json:"userId"`
+	BoardId int64  `json:"boardId"`
+	// Role is the role on the board of the user who got the status
+	Role     BoardRole `json:"-"`
+	Position int64     `json:"position"`
+	Color    string    `json:"color"`
+	// WipLimit is the max of todos on the status, nil without limit
+	WipLimit  *int64         `json:"wipLimit"`
+	Category  StatusCategory `json:"category"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+var (
+	ErrStatusCategoryInvalid = errors.New("category should be backlog, todo, in-progress, done or cancelled")
+)
+
+// StatusCategory says what a status means, whatever its name
+type StatusCategory string
+
+const (
+	StatusCategoryBacklog    StatusCategory = "backlog"
+	StatusCategoryTodo       StatusCategory = "todo"
+	StatusCategoryInProgress StatusCategory = "in-progress"
+	// StatusCategoryDone stamps the completedAt of the todos
+	StatusCategoryDone      StatusCategory = "done"
+	StatusCategoryCancelled StatusCategory = "cancelled"
+)
+
+func (category StatusCategory) Valid() error {
+	switch category {
+	case StatusCategoryBacklog, StatusCategoryTodo, StatusCategoryInProgress, StatusCategoryDone, StatusCategoryCancelled:
+		return nil
+	}
+	return ErrStatusCategoryInvalid
+}
+
+const (
+	TodoQueryDefaultLimit = 20
+	TodoQueryMaxLimit     = 100
+)
+
+var (
+	ErrTodoSortInvalid      = errors.New("sort should be createdAt, updatedAt, title, urgency or position")
+	ErrSortDirectionInvalid = errors.New("direction should be asc or desc")
+	ErrTodoLimitInvalid     = errors.New("limit should be between 1 and 100")
+	ErrCreatedRangeInvalid  = errors.New("createdAfter should be before createdBefore")
+	ErrTodoCursorInvalid    = errors.New("cursor is invalid")
+	ErrDueRangeInvalid      = errors.New("dueAfter should be before dueBefore")
+	ErrDueFilterInvalid     = errors.New("due should be overdue, dueToday or dueThisWeek")
+)
+
+type TodoSort string
+
+const (
+	TodoSortCreatedAt TodoSort = "createdAt"
+	TodoSortUpdatedAt TodoSort = "updatedAt"
+	TodoSortTitle     TodoSort = "title"
+	// TodoSortUrgency combines priority, due date and age
+	TodoSortUrgency TodoSort = "urgency"
+	// TodoSortPosition is the order of the todos on the status column
+	TodoSortPosition TodoSort = "position"
+)
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// TodoQuery is the spec of a todo list, the todos are filtered, sorted
+// and paginated by keyset from the cursor
+type TodoQuery struct {
+	UserId        int64
+	BoardId       int64
+	StatusId      int64
+	Search        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// DueFrom is inclusive and DueTo exclusive, set from the due filter
+	DueFrom *time.Time
+	DueTo   *time.Time
+	// Open keeps the todos not completed, a done todo is never overdue
+	Open       bool
+	Priorities []Priority
+	// Labels are lower case names without duplicates
+	Labels    []string
+	LabelMode LabelMode
+	// AssigneeId keeps the todos assigned to the user
+	AssigneeId int64
+	Sort       TodoSort
+	Direction  SortDirection
+	Cursor     *TodoCursor
+	Limit      int
+	// Now is the reference time of the urgency, kept by the cursor between pages
+	Now time.Time
+}
+
+func (query *TodoQuery) Valid() error {
+	switch query.Sort {
+	case TodoSortCreatedAt, TodoSortUpdatedAt, TodoSortTitle, TodoSortUrgency, TodoSortPosition:
+	default:
+		return ErrTodoSortInvalid
+	}
+	if query.Direction != SortAsc && query.Direction != SortDesc {
+		return ErrSortDirectionInvalid
+	}
+	if query.Limit < 1 || query.Limit > TodoQueryMaxLimit {
+		return ErrTodoLimitInvalid
+	}
+	if query.CreatedAfter != nil && query.CreatedBefore != nil && query.CreatedAfter.After(*query.CreatedBefore) {
+		return ErrCreatedRangeInvalid
+	}
+	if query.DueFrom != nil && query.DueTo != nil && query.DueFrom.After(*query.DueTo) {
+		return ErrDueRangeInvalid
+	}
+	for _, priority := range query.Priorities {
+		if err := priority.Valid(); err != nil {
+			return err
+		}
+	}
+	if len(query.Labels) > 0 && query.LabelMode != LabelModeAny && query.LabelMode != LabelModeAll {
+		return ErrLabelModeInvalid
+	}
+	if query.Cursor != nil && (query.Cursor.Sort != query.Sort || query.Cursor.Direction != query.Direction) {
+		return ErrTodoCursorInvalid
+	}
+	return nil
+}
+
+// TodoCursor points to the last todo of a page by its sort value and id
+type TodoCursor struct {
+	Sort      TodoSort      `json:"s"`
+	Direction SortDirection `json:"d"`
+	Value     string        `json:"v"`
+	ID        int64         `json:"id"`
+	// At is the reference time of the urgency sort
+	At string `json:"at,omitempty"`
+}
+
+func NewTodoCursor(query TodoQuery, todo *Todo) *TodoCursor {
+	cursor := &TodoCursor{Sort: query.Sort, Direction: query.Direction, ID: todo.ID}
+	switch query.Sort {
+	case TodoSortCreatedAt:
+		cursor.Value = todo.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case TodoSortUpdatedAt:
+		cursor.Value = todo.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case TodoSortTitle:
+		cursor.Value = todo.Title
+	case TodoSortPosition:
+		cursor.Value = todo.Position
+	case TodoSortUrgency:
+		if todo.Urgency != nil {
+			cursor.Value = strconv.FormatFloat(*todo.Urgency, 'f', 4, 64)
+		}
+		cursor.At = query.Now.UTC().Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+// Encode makes the opaque cursor sent to the client
+func (cursor *TodoCursor) Encode() string {
+	cursorJson, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(cursorJson)
+}
+
+func DecodeTodoCursor(cursorEncoded string) (*TodoCursor, error) {
+	cursorJson, err := base64.RawURLEncoding.DecodeString(cursorEncoded)
+	if err != nil {
+		return nil, ErrTodoCursorInvalid
+	}
+	var cursor TodoCursor
+	err = json.Unmarshal(cursorJson, &cursor)
+	if err != nil || cursor.ID <= 0 {
+		return nil, ErrTodoCursorInvalid
+	}
+	switch cursor.Sort {
+	case TodoSortCreatedAt, TodoSortUpdatedAt:
+		_, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	case TodoSortUrgency:
+		_, err = strconv.ParseFloat(cursor.Value, 64)
+		if err == nil {
+			_, err = cursor.Now()
+		}
+	}
+	if err != nil {
+		return nil, ErrTodoCursorInvalid
+	}
+	return &cursor, nil
+}
+
+// SortValue is the cursor value typed like the sort column
+func (cursor *TodoCursor) SortValue() interface{} {
+	if cursor.Sort == TodoSortTitle || cursor.Sort == TodoSortUrgency || cursor.Sort == TodoSortPosition {
+		return cursor.Value
+	}
+	value, _ := time.Parse(time.RFC3339Nano, cursor.Value)
+	return value
+}
+
+func (cursor *TodoCursor) Now() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, cursor.At)
+}
+
+type TodoPage struct {
+	Todos      []*Todo
+	NextCursor *TodoCursor
+	Total      int64
+}
+
+func (page *TodoPage) ToDtoHttpResponse() *TodoPageDtoHttpResponse {
+	response := &TodoPageDtoHttpResponse{
+		Todos: make([]*TodoDtoHttpResponse, 0, len(page.Todos)),
+		Total: page.Total,
+	}
+	for _, todo := range page.Todos {
+		response.Todos = append(response.Todos, todo.ToDtoHttpResponse())
+	}
+	if page.NextCursor != nil {
+		response.NextCursor = page.NextCursor.Encode()
+	}
+	return response
+}
+
+type TodoPageDtoHttpResponse struct {
+	Todos      []*TodoDtoHttpResponse `json:"todos"`
+	NextCursor string                 `json:"nextCursor"`
+	Total      int64                  `json:"total"`
+}
+
+// TrashKind is what a row of the trash is
+type TrashKind string
+
+const (
+	TrashKindTodo   TrashKind = "todo"
+	TrashKindStatus TrashKind = "status"
+)
+
+var (
+	ErrTrashKindInvalid = errors.New("kind should be todo or status")
+)
+
+func (kind TrashKind) Valid() error {
+	switch kind {
+	case TrashKindTodo, TrashKindStatus:
+		return nil
+	}
+	return ErrTrashKindInvalid
+}
+
+// TrashItem is a deleted todo or status, StatusId is the status of a todo
+// and PurgeAt when it is deleted for good
+type TrashItem struct {
+	Kind     TrashKind `json:"kind"`
+	ID       int64     `json:"id"`
+	Name     string    `json:"name"`
+	BoardId  int64     `json:"boardId"`
+	StatusId *int64    `json:"statusId"`
+	// Role is the role on the board of the user who got the item
+	Role      BoardRole `json:"-"`
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"`
+}
+
+// TrashRestore has every todo and status back from the trash with the item
+type TrashRestore struct {
+	TodoIds   []int64 `json:"todoIds"`
+	StatusIds []int64 `json:"statusIds"`
+}
+
+// DueFilter selects todos by due date relative to now in the user's timezone
+type DueFilter string
+
+const (
+	DueOverdue  DueFilter = "overdue"
+	DueToday    DueFilter = "dueToday"
+	DueThisWeek DueFilter = "dueThisWeek"
+	// the first names of the filters, still accepted
+	dueTodayLegacy    DueFilter = "today"
+	dueThisWeekLegacy DueFilter = "thisWeek"
+)
+
+// Range returns the due interval [from, to) of the filter, weeks start on monday
+func (filter DueFilter) Range(now time.Time, location *time.Location) (from, to *time.Time, err error) {
+	now = now.In(location)
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	switch filter {
+	case DueOverdue:
+		return nil, &now, nil
+	case DueToday, dueTodayLegacy:
+		endOfDay := startOfDay.AddDate(0, 0, 1)
+		return &startOfDay, &endOfDay, nil
+	case DueThisWeek, dueThisWeekLegacy:
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		startOfWeek := startOfDay.AddDate(0, 0, -daysSinceMonday)
+		endOfWeek := startOfWeek.AddDate(0, 0, 7)
+		return &startOfWeek, &endOfWeek, nil
+	}
+	return nil, nil, ErrDueFilterInvalid
+}
@@ -0,0 +1,135 @@
+package todos
+
+import (
+	"errors"
+	"strings"
+)
+
+// rankDigits are sorted like the "C" collation of the position column
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+var (
+	ErrRankInvalid = errors.New("rank should have only 0-9 and a-z and not end with 0")
+	ErrRankOrder   = errors.New("rank previous should be before rank next")
+)
+
+// RankBetween returns a rank sorted between previous and next, an empty
+// previous is the start of the column and an empty next is its end.
+// The ranks never end with the first digit, so there is always room before them.
+func RankBetween(previous, next string) (string, error) {
+	if !validRank(previous) || !validRank(next) {
+		return "", ErrRankInvalid
+	}
+	if next != "" && previous >= next {
+		return "", ErrRankOrder
+	}
+	switch {
+	case previous != "" && next == "":
+		return rankAfter(previous), nil
+	case previous == "" && next != "":
+		return rankBefore(next), nil
+	}
+	return rankMidpoint(previous, next), nil
+}
+
+// rankAfter counts up from the rank on its length, the last rank of a length
+// goes on twice its length. The ranks appended to a column grow with the
+// logarithm of their count.
+func rankAfter(previous string) string {
+	last := rankDigits[len(rankDigits)-1:]
+	if strings.Trim(previous, last) == "" {
+		return previous + strings.Repeat(rankDigits[:1], len(previous)-1) + rankDigits[1:2]
+	}
+	rank := rankStep(previous, 1)
+	if !validRank(rank) {
+		rank = rankStep(rank, 1)
+	}
+	return rank
+}
+
+// rankBefore counts down from the rank like rankAfter counts up
+func rankBefore(next string) string {
+	first := rankDigits[:1]
+	if next == strings.Repeat(first, len(next)-1)+rankDigits[1:2] {
+		return strings.Repeat(first, len(next)) + strings.Repeat(rankDigits[len(rankDigits)-1:], len(next))
+	}
+	rank := rankStep(next, -1)
+	if !validRank(rank) {
+		rank = rankStep(rank, -1)
+	}
+	return rank
+}
+
+// rankStep adds the step of 1 or -1 to the rank read as a number in base 36,
+// the rank keeps its length so it shouldn't be its last or its first one
+func rankStep(rank string, step int) string {
+	digits := []byte(rank)
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := strings.IndexByte(rankDigits, digits[i]) + step
+		if digit >= 0 && digit < len(rankDigits) {
+			digits[i] = rankDigits[digit]
+			break
+		}
+		digits[i] = rankDigits[(digit+len(rankDigits))%len(rankDigits)]
+	}
+	return string(digits)
+}
+
+func validRank(rank string) bool {
+	if strings.HasSuffix(rank, rankDigits[:1]) {
+		return false
+	}
+	for _, digit := range rank {
+		if !strings.ContainsRune(rankDigits, digit) {
+			return false
+		}
+	}
+	return true
+}
+
+// rankMidpoint expects previous < next, the missing digits of previous are
+// the first digit and the missing digits of an empty next are past the last one
+func rankMidpoint(previous, next string) string {
+	if next != "" {
+		// keep the common prefix, previous padded with the first digit
+		prefix := 0
+		for prefix < len(next) && rankDigitAt(previous, prefix) == next[prefix] {
+			prefix++
+		}
+		if prefix > 0 {
+			return next[:prefix] + rankMidpoint(rankTail(previous, prefix), next[prefix:])
+		}
+	}
+
+	previousDigit := 0
+	if previous != "" {
+		previousDigit = strings.IndexByte(rankDigits, previous[0])
+	}
+	nextDigit := len(rankDigits)
+	if next != "" {
+		nextDigit = strings.IndexByte(rankDigits, next[0])
+	}
+	if nextDigit-previousDigit > 1 {
+		return string(rankDigits[(previousDigit+nextDigit)/2])
+	}
+	// the first digits are consecutive, next cut to its first digit is
+	// still after previous when it has more digits
+	if len(next) > 1 {
+		return next[:1]
+	}
+	return string(rankDigits[previousDigit]) + rankMidpoint(rankTail(previous, 1), "")
+}
+
+func rankDigitAt(rank string, index int) byte {
+	if index < len(rank) {
+		return rank[index]
+	}
+	return rankDigits[0]
+}
+
+func rankTail(rank string, index int) string {
+	if index < len(rank) {
+		return rank[index:]
+	}
+	return ""
+}
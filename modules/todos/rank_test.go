@@ -0,0 +1,110 @@
+package todos_test
+
+import (
+	"api/modules/todos"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	cases := []struct {
+		name     string
+		previous string
+		next     string
+		rank     string
+		err      error
+	}{
+		{"empty column", "", "", "i", nil},
+		{"append", "i", "", "j", nil},
+		{"append carries", "az", "", "b1", nil},
+		{"append after the last of a length", "z", "", "z1", nil},
+		{"append doubles the length", "zz", "", "zz01", nil},
+		{"prepend", "", "i", "h", nil},
+		{"prepend borrows", "", "b1", "az", nil},
+		{"prepend before the first of a length", "", "1", "0z", nil},
+		{"prepend doubles the length", "", "01", "00zz", nil},
+		{"between", "a", "c", "b", nil},
+		{"between consecutive", "a", "b", "ai", nil},
+		{"between longer next", "a", "b5", "b", nil},
+		{"between common prefix", "a1", "a3", "a2", nil},
+		{"previous after next", "c", "a", "", todos.ErrRankOrder},
+		{"same ranks", "a", "a", "", todos.ErrRankOrder},
+		{"trailing first digit", "a0", "", "", todos.ErrRankInvalid},
+		{"invalid digit", "", "A", "", todos.ErrRankInvalid},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rank, err := todos.RankBetween(tc.previous, tc.next)
+			if err != tc.err {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if rank != tc.rank {
+				t.Fatalf("expected rank %q, got %q", tc.rank, rank)
+			}
+		})
+	}
+}
+
+// checkRank fails when the rank isn't valid or isn't between its neighbors
+func checkRank(t *testing.T, previous, rank, next string) {
+	t.Helper()
+	if _, err := todos.RankBetween(rank, ""); err != nil {
+		t.Fatalf("rank %q after %q is invalid: %v", rank, previous, err)
+	}
+	if rank <= previous || (next != "" && rank >= next) {
+		t.Fatalf("rank %q should be between %q and %q", rank, previous, next)
+	}
+}
+
+func TestRankBetweenLength(t *testing.T) {
+	cases := []struct {
+		name      string
+		count     int
+		append    bool
+		maxLength int
+	}{
+		{"1000 appends", 1000, true, 4},
+		{"20000 appends", 20000, true, 8},
+		{"1000 prepends", 1000, false, 4},
+		{"20000 prepends", 20000, false, 8},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rank := ""
+			for i := 0; i < tc.count; i++ {
+				var next string
+				var err error
+				if tc.append {
+					next, err = todos.RankBetween(rank, "")
+					if err == nil {
+						checkRank(t, rank, next, "")
+					}
+				} else {
+					next, err = todos.RankBetween("", rank)
+					if err == nil {
+						checkRank(t, "", next, rank)
+					}
+				}
+				if err != nil {
+					t.Fatalf("rank %d: %v", i, err)
+				}
+				rank = next
+			}
+			if len(rank) > tc.maxLength {
+				t.Fatalf("expected at most %d digits, got %d in %q", tc.maxLength, len(rank), rank)
+			}
+		})
+	}
+}
+
+func TestRankBetweenInserts(t *testing.T) {
+	// always inserting after the first rank keeps the column sorted
+	ranks := []string{"i", "j"}
+	for i := 0; i < 200; i++ {
+		rank, err := todos.RankBetween(ranks[0], ranks[1])
+		if err != nil {
+			t.Fatalf("insert %d: %v", i, err)
+		}
+		checkRank(t, ranks[0], rank, ranks[1])
+		ranks = append([]string{ranks[0], rank}, ranks[1:]...)
+	}
+}
@@ -4,27 +4,45 @@ import (
 	"bytes"
 	"database/sql"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type TodoRepository interface {
-	InsertTodo(title, description string, statusId int64) (*Todo, error)
-	UpdateTodo(todoId int64, title, description string, statusTodoId int64) error
-	DeleteTodo(todoId int64) error
-	GetTodo(todoID int64) (*Todo, error)
-	GetAllTodo() ([]*Todo, error)
+	InsertTodo(userId int64, title, description string, statusId int64, startAt, dueAt *time.Time, priority Priority, parentId *int64, completedAt *time.Time) (*Todo, error)
+	UpdateTodo(userId int64, todo *Todo, title, description string, statusTodoId int64, startAt, dueAt *time.Time, priority Priority, completedAt *time.Time) error
+	DeleteTodo(userId int64, todo *Todo) error
+	GetTodo(userId, todoID int64) (*Todo, error)
+	GetAllTodo(query TodoQuery) ([]*Todo, error)
+	CountTodo(query TodoQuery) (int64, error)
 	CountTodoByStatus(statusTodoId int64) (int64, error)
 
-	UpdateImageTodo(todoID int64, image *bytes.Buffer) error
-	GetImageTodo(todoID int64) (*bytes.Buffer, error)
+	GetChildrenTodo(userId, todoId int64, recursive bool) ([]*Todo, error)
+	CountChildrenTodo(todoId int64) (int64, error)
+	MoveTodo(userId int64, todo *Todo, parentId *int64) (moved bool, err error)
+	DeleteTodoCascade(userId, todoId int64) (deleted int64, err error)
+	DeleteTodoReparent(userId, todoId int64) error
+
+	PositionTodo(userId int64, todo *Todo, statusId int64, previousId, nextId *int64, completedAt *time.Time) (position string, err error)
 
-	InsertStatusTodo(name string, userId int64) (*StatusTodo, error)
-	UpdateStatusTodo(statusId int64, name string, userId int64) error
-	GetAllStatusTodo() ([]*StatusTodo, error)
+	UpdateImageTodo(userId int64, todo *Todo, image *bytes.Buffer) error
+	GetImageTodo(userId, todoID int64) (*bytes.Buffer, error)
+
+	InsertStatusTodo(name string, userId, boardId int64, color string, wipLimit *int64, category StatusCategory) (*StatusTodo, error)
+	UpdateStatusTodo(statusId int64, name string, userId int64, color string, wipLimit *int64, category StatusCategory) error
+	ReorderStatusTodo(userId int64, statusIds []int64) error
+	GetAllStatusTodo(userId, boardId int64) ([]*StatusTodo, error)
 	GetStatusTodo(userId int64, statusId int64) (*StatusTodo, error)
-	GetStatusTodoByName(userId int64, name string) (*StatusTodo, error)
+	GetStatusTodoByName(userId, boardId int64, name string) (*StatusTodo, error)
 	DeleteStatusTodo(userId int64, statusID int64) error
+	DeleteStatusTodoMovingTodos(userId, statusId, toStatusId int64) (moved int64, err error)
+	DeleteStatusTodoCascade(userId, statusId int64) (deleted int64, err error)
+
+	GetStatusTransitions(statusId int64) (toStatusIds []int64, err error)
+	ReplaceStatusTransitions(statusId int64, toStatusIds []int64) error
 }
 
 type TodoRepositoryPG struct {
@@ -35,64 +53,368 @@ func NewTodoRepository(db *sql.DB) TodoRepository {
 	return &TodoRepositoryPG{db}
 }
 
-func (repo *TodoRepositoryPG) InsertTodo(title, description string, statusID int64) (*Todo, error) {
+// InsertTodo adds the todo at the end of its status column, created by the
+// user in its history
+func (repo *TodoRepositoryPG) InsertTodo(userId int64, title, description string, statusID int64, startAt, dueAt *time.Time, priority Priority, parentId *int64, completedAt *time.Time) (*Todo, error) {
 	var todo Todo
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	position, err := lastTodoPosition(tx, statusID, 0)
+	if err != nil {
+		return nil, err
+	}
+	err = checkWipLimit(tx, statusID, 0)
+	if err != nil {
+		return nil, err
+	}
+
 	sqlInsert := `
-		INSERT INTO todos.todo (title, description, tstts_id)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at, updated_at;
+		INSERT INTO todos.todo (title, description, tstts_id, start_at, due_at, priority, parent_id, position, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at, (SELECT board_id FROM todos.todo_status WHERE id=$3);
 	`
-	args := []interface{}{title, description, statusID}
-	row := repo.db.QueryRow(sqlInsert, args...)
+	args := []interface{}{title, description, statusID, startAt, dueAt, priority, parentId, position, completedAt}
+	row := tx.QueryRow(sqlInsert, args...)
 	if row.Err() != nil {
 		return nil, row.Err()
 	}
-	err := row.Scan(&todo.ID, &todo.CreatedAt, &todo.UpdatedAt)
+	err = row.Scan(&todo.ID, &todo.CreatedAt, &todo.UpdatedAt, &todo.BoardId)
 	if err != nil {
 		return nil, err
 	}
+	todo.Position = position
+	todo.CompletedAt = completedAt
 	todo.Title = title
 	todo.Description = description
 	todo.StatusID = statusID
+	todo.StartAt = startAt
+	todo.DueAt = dueAt
+	todo.Priority = priority
+	todo.ParentId = parentId
+
+	err = insertTodoChange(tx, userId, TodoEventCreated, nil, &todo)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
 	return &todo, nil
 }
 
-func (repo *TodoRepositoryPG) UpdateTodo(todoId int64, title, description string, statusTodoId int64) error {
+// UpdateTodo keeps the position on the same status, a todo moved to
+// another status goes to the end of its column. The history has the change
+// from the todo given.
+func (repo *TodoRepositoryPG) UpdateTodo(userId int64, todo *Todo, title, description string, statusTodoId int64, startAt, dueAt *time.Time, priority Priority, completedAt *time.Time) error {
 	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	position, err := lastTodoPosition(tx, statusTodoId, todo.ID)
+	if err != nil {
+		return err
+	}
+	err = checkWipLimit(tx, statusTodoId, todo.ID)
+	if err != nil {
+		return err
+	}
+
 	sqlUpdate := `
 		UPDATE todos.todo
 		SET 
 			title=$2,
 			description=$3,
+			position=CASE WHEN tstts_id=$4 THEN position ELSE $10 END,
 			tstts_id=$4,
-			updated_at=$5
+			updated_at=$5,
+			start_at=$7,
+			due_at=$8,
+			priority=$9,
+			completed_at=$11
+		WHERE 
+			id=$1 AND
+			tstts_id IN (SELECT id FROM todos.todo_status WHERE board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$6))
+		RETURNING position;
+	`
+	after := *todo
+	args := []interface{}{todo.ID, title, description, statusTodoId, now, userId, startAt, dueAt, priority, position, completedAt}
+	err = tx.QueryRow(sqlUpdate, args...).Scan(&after.Position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	after.Title = title
+	after.Description = description
+	after.StatusID = statusTodoId
+	after.StartAt = startAt
+	after.DueAt = dueAt
+	after.Priority = priority
+	after.CompletedAt = completedAt
+
+	err = insertTodoChange(tx, userId, TodoEventUpdated, todo, &after)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockStatusTodo serializes the changes of positions in the status column
+// until the end of the transaction
+func lockStatusTodo(tx *sql.Tx, statusId int64) error {
+	sqlLock := `
+		SELECT id
+		FROM todos.todo_status
 		WHERE id=$1
+		FOR UPDATE;
 	`
-	args := []interface{}{todoId, title, description, statusTodoId, now}
-	_, err := repo.db.Exec(sqlUpdate, args...)
+	_, err := tx.Exec(sqlLock, statusId)
 	return err
 }
 
-func (repo *TodoRepositoryPG) DeleteTodo(todoID int64) error {
-	sqlDelete := `
-		DELETE FROM todos.todo
-		WHERE id=$1;
+// lockBoard serializes the changes of the board and of its statuses until
+// the end of the transaction
+func lockBoard(tx *sql.Tx, boardId int64) error {
+	sqlLock := `
+		SELECT id
+		FROM todos.board
+		WHERE id=$1
+		FOR UPDATE;
 	`
-	_, err := repo.db.Exec(sqlDelete, todoID)
+	_, err := tx.Exec(sqlLock, boardId)
 	return err
 }
 
-func (repo *TodoRepositoryPG) GetTodo(todoId int64) (*Todo, error) {
+// lastTodoPosition locks the status column and returns a position after
+// its last todo, the todo excluded is ignored
+func lastTodoPosition(tx *sql.Tx, statusId, excludedTodoId int64) (string, error) {
+	err := lockStatusTodo(tx, statusId)
+	if err != nil {
+		return "", err
+	}
+	var last string
+	sqlLast := `
+		SELECT COALESCE(MAX(position), '')
+		FROM todos.todo
+		WHERE 
+			tstts_id=$1 AND
+			id<>$2 AND
+			deleted_at IS NULL;
+	`
+	err = tx.QueryRow(sqlLast, statusId, excludedTodoId).Scan(&last)
+	if err != nil {
+		return "", err
+	}
+	return RankBetween(last, "")
+}
+
+// checkWipLimit returns ErrStatusWipLimitReached when the todo can't go to
+// the status, the status should be locked. A todo already on the status stays.
+func checkWipLimit(tx *sql.Tx, statusId, todoId int64) error {
+	var wipLimit sql.NullInt64
+	var count int64
+	var onStatus bool
+	sqlCheck := `
+		SELECT
+			ts.wip_limit,
+			(SELECT COUNT(*) FROM todos.todo WHERE tstts_id=ts.id AND id<>$2 AND deleted_at IS NULL),
+			EXISTS (SELECT 1 FROM todos.todo WHERE tstts_id=ts.id AND id=$2 AND deleted_at IS NULL)
+		FROM todos.todo_status ts
+		WHERE ts.id=$1;
+	`
+	err := tx.QueryRow(sqlCheck, statusId, todoId).Scan(&wipLimit, &count, &onStatus)
+	if err != nil {
+		return err
+	}
+	if wipLimit.Valid && !onStatus && count >= wipLimit.Int64 {
+		return ErrStatusWipLimitReached
+	}
+	return nil
+}
+
+// PositionTodo moves the todo to the status between the previous and the next
+// todos, only its row is updated. A missing neighbor is the one next to the
+// other, without both the todo goes to the end. The moves on the same status
+// are serialized by the lock of its row, neighbors that are no longer side by
+// side return ErrTodoMoveConflict.
+func (repo *TodoRepositoryPG) PositionTodo(userId int64, todo *Todo, statusId int64, previousId, nextId *int64, completedAt *time.Time) (string, error) {
+	todoId := todo.ID
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	err = lockStatusTodo(tx, statusId)
+	if err != nil {
+		return "", err
+	}
+	err = checkWipLimit(tx, statusId, todoId)
+	if err != nil {
+		return "", err
+	}
+
+	var previous, next string
+	if previousId != nil {
+		previous, err = neighborTodoPosition(tx, statusId, todoId, *previousId)
+		if err != nil {
+			return "", err
+		}
+	}
+	if nextId != nil {
+		next, err = neighborTodoPosition(tx, statusId, todoId, *nextId)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// the closest todos around the neighbors given
+	var closestPrevious, closestNext sql.NullString
+	sqlClosest := `
+		SELECT
+			(SELECT MAX(position) FROM todos.todo WHERE tstts_id=$1 AND id<>$2 AND deleted_at IS NULL AND ($3='' OR position<$3)),
+			(SELECT MIN(position) FROM todos.todo WHERE tstts_id=$1 AND id<>$2 AND deleted_at IS NULL AND position>$4);
+	`
+	err = tx.QueryRow(sqlClosest, statusId, todoId, next, previous).Scan(&closestPrevious, &closestNext)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case previousId != nil && nextId != nil:
+		if closestNext.String != next {
+			return "", ErrTodoMoveConflict
+		}
+	case previousId != nil:
+		next = closestNext.String
+	case nextId != nil:
+		previous = closestPrevious.String
+	default:
+		previous = closestPrevious.String
+	}
+
+	position, err := RankBetween(previous, next)
+	if err != nil {
+		return "", ErrTodoMoveConflict
+	}
+
+	sqlUpdate := `
+		UPDATE todos.todo
+		SET 
+			tstts_id=$3,
+			position=$4,
+			updated_at=$5,
+			completed_at=$6
+		WHERE 
+			id=$1 AND
+			tstts_id IN (SELECT id FROM todos.todo_status WHERE board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2));
+	`
+	result, err := tx.Exec(sqlUpdate, todoId, userId, statusId, position, now, completedAt)
+	if err != nil {
+		return "", err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if rowsAffected > 0 {
+		after := *todo
+		after.StatusID = statusId
+		after.Position = position
+		after.CompletedAt = completedAt
+		err = insertTodoChange(tx, userId, TodoEventMoved, todo, &after)
+		if err != nil {
+			return "", err
+		}
+	}
+	return position, tx.Commit()
+}
+
+func neighborTodoPosition(tx *sql.Tx, statusId, todoId, neighborId int64) (string, error) {
+	if neighborId == todoId {
+		return "", ErrTodoNeighborNotFound
+	}
+	var position string
+	sqlGet := `
+		SELECT position
+		FROM todos.todo
+		WHERE 
+			id=$1 AND
+			tstts_id=$2 AND
+			deleted_at IS NULL;
+	`
+	err := tx.QueryRow(sqlGet, neighborId, statusId).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrTodoNeighborNotFound
+	}
+	return position, err
+}
+
+// DeleteTodo puts the todo in the trash
+func (repo *TodoRepositoryPG) DeleteTodo(userId int64, todo *Todo) error {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlDelete := `
+		UPDATE todos.todo
+		SET deleted_at=$3
+		WHERE 
+			id=$1 AND
+			deleted_at IS NULL AND
+			tstts_id IN (SELECT id FROM todos.todo_status WHERE board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2));
+	`
+	args := []interface{}{todo.ID, userId, now}
+	result, err := tx.Exec(sqlDelete, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		err = insertTodoChange(tx, userId, TodoEventDeleted, todo, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (repo *TodoRepositoryPG) GetTodo(userId, todoId int64) (*Todo, error) {
 	var todo Todo
 	var bufferImage = []byte{}
 
 	sqlGet := `
-		SELECT id, title, description, created_at, updated_at, tstts_id, image
-		FROM todos.todo
-		WHERE id=$1;
+		SELECT t.id, t.title, t.description, t.created_at, t.updated_at, t.tstts_id, t.start_at, t.due_at, t.priority, t.parent_id, t.position, t.completed_at, ts.board_id, bm.role, t.image,
+			(SELECT COUNT(*) FILTER (WHERE ci.done) FROM todos.checklist_item ci WHERE ci.todo_id = t.id),
+			(SELECT COUNT(*) FROM todos.checklist_item ci WHERE ci.todo_id = t.id),
+			` + todoLabelsExpression + `,
+			` + todoAssigneesExpression + `,
+			(SELECT COUNT(*) FROM todos.comment c WHERE c.todo_id = t.id)
+		FROM todos.todo t
+		INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+		INNER JOIN todos.board_member bm ON bm.board_id = ts.board_id
+		WHERE 
+			t.id=$1 AND
+			t.deleted_at IS NULL AND
+			bm.user_id=$2;
 	`
 
-	row := repo.db.QueryRow(sqlGet, todoId)
+	row := repo.db.QueryRow(sqlGet, todoId, userId)
 	if row.Err() != nil {
 		return nil, row.Err()
 	}
@@ -104,7 +426,20 @@ func (repo *TodoRepositoryPG) GetTodo(todoId int64) (*Todo, error) {
 		&todo.CreatedAt,
 		&todo.UpdatedAt,
 		&todo.StatusID,
+		&todo.StartAt,
+		&todo.DueAt,
+		&todo.Priority,
+		&todo.ParentId,
+		&todo.Position,
+		&todo.CompletedAt,
+		&todo.BoardId,
+		&todo.Role,
 		&bufferImage,
+		&todo.ChecklistDone,
+		&todo.ChecklistTotal,
+		&todo.Labels,
+		&todo.Assignees,
+		&todo.CommentCount,
 	)
 
 	if err != nil {
@@ -117,26 +452,51 @@ func (repo *TodoRepositoryPG) GetTodo(todoId int64) (*Todo, error) {
 	if len(bufferImage) > 0 {
 		reader := bytes.NewReader(bufferImage)
 		todo.Image.ReadFrom(reader)
+		todo.HasImage = true
 	}
 
 	return &todo, nil
 }
 
-func (repo *TodoRepositoryPG) GetAllTodo() ([]*Todo, error) {
+// GetAllTodo returns the page of the query without the image column
+func (repo *TodoRepositoryPG) GetAllTodo(query TodoQuery) ([]*Todo, error) {
 	var todos = make([]*Todo, 0)
-	sqlGet := `
-		SELECT id, title, description, created_at, updated_at, tstts_id, image
-		FROM todos.todo;
-	`
-	rows, err := repo.db.Query(sqlGet)
+	builder := newTodoQueryBuilder(query)
+	sortExpression := builder.sortExpression()
+	builder.whereCursor(sortExpression)
+
+	direction := "ASC"
+	if query.Direction == SortDesc {
+		direction = "DESC"
+	}
+	// the urgency is only computed when sorting by it
+	urgencyExpression := "NULL::numeric"
+	if query.Sort == TodoSortUrgency {
+		urgencyExpression = sortExpression
+	}
+	sqlGet := fmt.Sprintf(`
+		SELECT t.id, t.title, t.description, t.created_at, t.updated_at, t.tstts_id, t.start_at, t.due_at, t.priority, t.parent_id, t.position, t.completed_at, ts.board_id, t.image IS NOT NULL, %s,
+			(SELECT COUNT(*) FILTER (WHERE ci.done) FROM todos.checklist_item ci WHERE ci.todo_id = t.id),
+			(SELECT COUNT(*) FROM todos.checklist_item ci WHERE ci.todo_id = t.id),
+			`+todoLabelsExpression+`,
+			`+todoAssigneesExpression+`,
+			(SELECT COUNT(*) FROM todos.comment c WHERE c.todo_id = t.id)
+		FROM todos.todo t
+		INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+		WHERE %s
+		ORDER BY %s %s, t.id %s
+		LIMIT %s;
+	`, urgencyExpression, builder.where(), sortExpression, direction, direction, builder.arg(query.Limit))
+
+	rows, err := repo.db.Query(sqlGet, builder.args...)
 	if err != nil {
-		return nil, nil
+		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var todo Todo
-		var bufferImage = []byte{}
-
+		var urgency sql.NullFloat64
 		err = rows.Scan(
 			&todo.ID,
 			&todo.Title,
@@ -144,29 +504,217 @@ func (repo *TodoRepositoryPG) GetAllTodo() ([]*Todo, error) {
 			&todo.CreatedAt,
 			&todo.UpdatedAt,
 			&todo.StatusID,
-			&bufferImage,
+			&todo.StartAt,
+			&todo.DueAt,
+			&todo.Priority,
+			&todo.ParentId,
+			&todo.Position,
+			&todo.CompletedAt,
+			&todo.BoardId,
+			&todo.HasImage,
+			&urgency,
+			&todo.ChecklistDone,
+			&todo.ChecklistTotal,
+			&todo.Labels,
+			&todo.Assignees,
+			&todo.CommentCount,
 		)
 		if err != nil {
-			return nil, nil
+			return nil, err
 		}
+		if urgency.Valid {
+			todo.Urgency = &urgency.Float64
+		}
+		todos = append(todos, &todo)
+	}
+
+	return todos, rows.Err()
+}
 
-		if len(bufferImage) > 0 {
-			reader := bytes.NewReader(bufferImage)
-			todo.Image.ReadFrom(reader)
+// CountTodo counts every todo matching the query filters, the cursor is ignored
+func (repo *TodoRepositoryPG) CountTodo(query TodoQuery) (int64, error) {
+	var count int64
+	builder := newTodoQueryBuilder(query)
+	sqlCount := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM todos.todo t
+		INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+		WHERE %s;
+	`, builder.where())
+	row := repo.db.QueryRow(sqlCount, builder.args...)
+	if row.Err() != nil {
+		return -1, row.Err()
+	}
+	err := row.Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+	return count, nil
+}
+
+var todoSortColumns = map[TodoSort]string{
+	TodoSortCreatedAt: "t.created_at",
+	TodoSortUpdatedAt: "t.updated_at",
+	TodoSortTitle:     "t.title",
+	TodoSortPosition:  "t.position",
+}
+
+// todoUrgencyExpression scores from 0 to 100 at the reference time $1:
+// priority up to 40, due date up to 40 (overdue or due in the next 10 days,
+// nothing once completed) and age up to 20 (one point every 3 days)
+const todoUrgencyExpression = `ROUND((
+	t.priority * 10 +
+	CASE
+		WHEN t.due_at IS NULL OR t.completed_at IS NOT NULL THEN 0
+		WHEN t.due_at <= %[1]s::timestamptz THEN 40
+		ELSE GREATEST(0, 40 - EXTRACT(EPOCH FROM (t.due_at - %[1]s::timestamptz)) / 86400 * 4)
+	END +
+	LEAST(20, GREATEST(0, EXTRACT(EPOCH FROM (%[1]s::timestamptz - t.created_at AT TIME ZONE 'UTC')) / 86400 / 3))
+)::numeric, 4)`
+
+// todoLabelsExpression aggregates the labels of the todo as a json array
+const todoLabelsExpression = `(
+	SELECT COALESCE(json_agg(json_build_object('id', l.id, 'name', l.name, 'color', l.color) ORDER BY LOWER(l.name)), '[]')
+	FROM todos.todo_label tl
+	INNER JOIN todos.label l ON l.id = tl.label_id
+	WHERE tl.todo_id = t.id
+)`
+
+// userJsonExpression is the user u as a json object with the fields of
+// UserDtoHttpResponse
+const userJsonExpression = `json_build_object(
+	'id', u.id,
+	'name', u.name,
+	'username', u.username,
+	'photoUrl', CASE WHEN u.photo IS NULL THEN '' ELSE '/users/photo/' || u.id END
+)`
+
+// todoAssigneesExpression aggregates the assignees of the todo as a json
+// array, in the order they were assigned
+const todoAssigneesExpression = `(
+	SELECT COALESCE(json_agg(` + userJsonExpression + ` ORDER BY ta.created_at, u.id), '[]')
+	FROM todos.todo_assignee ta
+	INNER JOIN users.user u ON u.id = ta.user_id
+	WHERE ta.todo_id = t.id
+)`
+
+// todoQueryBuilder makes the where clause of a todo query, the columns
+// are fixed by the builder and every value is a placeholder
+type todoQueryBuilder struct {
+	query      TodoQuery
+	conditions []string
+	args       []interface{}
+}
+
+func newTodoQueryBuilder(query TodoQuery) *todoQueryBuilder {
+	builder := &todoQueryBuilder{query: query}
+	builder.filter()
+	return builder
+}
+
+// arg adds the value to the args and returns its placeholder
+func (builder *todoQueryBuilder) arg(value interface{}) string {
+	builder.args = append(builder.args, value)
+	return fmt.Sprintf("$%d", len(builder.args))
+}
+
+func (builder *todoQueryBuilder) filter() {
+	query := builder.query
+	builder.conditions = append(builder.conditions, "t.deleted_at IS NULL")
+	builder.conditions = append(builder.conditions, "ts.board_id IN (SELECT board_id FROM todos.board_member WHERE user_id="+builder.arg(query.UserId)+")")
+	if query.BoardId > 0 {
+		builder.conditions = append(builder.conditions, "ts.board_id="+builder.arg(query.BoardId))
+	}
+	if query.StatusId > 0 {
+		builder.conditions = append(builder.conditions, "t.tstts_id="+builder.arg(query.StatusId))
+	}
+	if query.AssigneeId > 0 {
+		builder.conditions = append(builder.conditions, "EXISTS (SELECT 1 FROM todos.todo_assignee ta WHERE ta.todo_id = t.id AND ta.user_id="+builder.arg(query.AssigneeId)+")")
+	}
+	if query.Search != "" {
+		search := builder.arg("%" + likeEscaper.Replace(query.Search) + "%")
+		builder.conditions = append(builder.conditions, "(t.title ILIKE "+search+" OR t.description ILIKE "+search+")")
+	}
+	if query.CreatedAfter != nil {
+		builder.conditions = append(builder.conditions, "t.created_at >= "+builder.arg(query.CreatedAfter.UTC()))
+	}
+	if query.CreatedBefore != nil {
+		builder.conditions = append(builder.conditions, "t.created_at <= "+builder.arg(query.CreatedBefore.UTC()))
+	}
+	if query.DueFrom != nil {
+		builder.conditions = append(builder.conditions, "t.due_at >= "+builder.arg(*query.DueFrom))
+	}
+	if query.DueTo != nil {
+		builder.conditions = append(builder.conditions, "t.due_at < "+builder.arg(*query.DueTo))
+	}
+	if query.Open {
+		builder.conditions = append(builder.conditions, "t.completed_at IS NULL")
+	}
+	if len(query.Priorities) > 0 {
+		priorities := make([]int64, 0, len(query.Priorities))
+		for _, priority := range query.Priorities {
+			priorities = append(priorities, int64(priority))
 		}
+		builder.conditions = append(builder.conditions, "t.priority = ANY("+builder.arg(pq.Array(priorities))+")")
+	}
+	if len(query.Labels) > 0 {
+		labels := builder.arg(pq.Array(query.Labels))
+		todoLabels := `(
+			SELECT COUNT(DISTINCT LOWER(l.name))
+			FROM todos.todo_label tl
+			INNER JOIN todos.label l ON l.id = tl.label_id
+			WHERE tl.todo_id = t.id AND LOWER(l.name) = ANY(` + labels + `)
+		)`
+		if query.LabelMode == LabelModeAll {
+			builder.conditions = append(builder.conditions, todoLabels+" = "+builder.arg(len(query.Labels)))
+		} else {
+			builder.conditions = append(builder.conditions, todoLabels+" > 0")
+		}
+	}
+}
 
-		todos = append(todos, &todo)
+func (builder *todoQueryBuilder) sortExpression() string {
+	if builder.query.Sort == TodoSortUrgency {
+		return fmt.Sprintf(todoUrgencyExpression, builder.arg(builder.query.Now.UTC()))
+	}
+	return todoSortColumns[builder.query.Sort]
+}
+
+// whereCursor keeps the todos after the cursor on the sort order
+func (builder *todoQueryBuilder) whereCursor(sortExpression string) {
+	cursor := builder.query.Cursor
+	if cursor == nil {
+		return
 	}
+	comparison := ">"
+	if builder.query.Direction == SortDesc {
+		comparison = "<"
+	}
+	cursorValue := builder.arg(cursor.SortValue())
+	if builder.query.Sort == TodoSortUrgency {
+		cursorValue += "::numeric"
+	}
+	builder.conditions = append(builder.conditions, fmt.Sprintf(
+		"(%s, t.id) %s (%s, %s)",
+		sortExpression,
+		comparison,
+		cursorValue,
+		builder.arg(cursor.ID),
+	))
+}
 
-	return todos, nil
+func (builder *todoQueryBuilder) where() string {
+	return strings.Join(builder.conditions, " AND ")
 }
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func (repo *TodoRepositoryPG) CountTodoByStatus(statusTodoId int64) (int64, error) {
 	var count int64
 	sqlCount := `
 		SELECT COUNT(*)
 		FROM todos.todo
-		WHERE tstts_id=$1
+		WHERE tstts_id=$1 AND deleted_at IS NULL
 	`
 	row := repo.db.QueryRow(sqlCount, statusTodoId)
 	if row.Err() != nil {
@@ -179,7 +727,275 @@ func (repo *TodoRepositoryPG) CountTodoByStatus(statusTodoId int64) (int64, erro
 	return count, nil
 }
 
-func (repo *TodoRepositoryPG) UpdateImageTodo(todoId int64, image *bytes.Buffer) error {
+// GetChildrenTodo returns the direct children, or the whole subtree when
+// recursive, ordered from the top of the tree
+func (repo *TodoRepositoryPG) GetChildrenTodo(userId, todoId int64, recursive bool) ([]*Todo, error) {
+	var todos = make([]*Todo, 0)
+	maxDepth := 1
+	if recursive {
+		maxDepth = todoMaxDepth
+	}
+	sqlGet := `
+		WITH RECURSIVE subtree AS (
+			SELECT t.id, 1 AS depth
+			FROM todos.todo t
+			WHERE t.parent_id=$1 AND t.deleted_at IS NULL
+			UNION
+			SELECT t.id, s.depth + 1
+			FROM todos.todo t
+			INNER JOIN subtree s ON t.parent_id = s.id
+			WHERE s.depth < $3 AND t.deleted_at IS NULL
+		)
+		SELECT t.id, t.title, t.description, t.created_at, t.updated_at, t.tstts_id, t.start_at, t.due_at, t.priority, t.parent_id, t.position, t.completed_at, ts.board_id, t.image IS NOT NULL,
+			(SELECT COUNT(*) FILTER (WHERE ci.done) FROM todos.checklist_item ci WHERE ci.todo_id = t.id),
+			(SELECT COUNT(*) FROM todos.checklist_item ci WHERE ci.todo_id = t.id),
+			` + todoLabelsExpression + `,
+			` + todoAssigneesExpression + `,
+			(SELECT COUNT(*) FROM todos.comment c WHERE c.todo_id = t.id)
+		FROM subtree s
+		INNER JOIN todos.todo t ON t.id = s.id
+		INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+		WHERE ts.board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2)
+		ORDER BY s.depth, t.created_at, t.id;
+	`
+	rows, err := repo.db.Query(sqlGet, todoId, userId, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var todo Todo
+		err = rows.Scan(
+			&todo.ID,
+			&todo.Title,
+			&todo.Description,
+			&todo.CreatedAt,
+			&todo.UpdatedAt,
+			&todo.StatusID,
+			&todo.StartAt,
+			&todo.DueAt,
+			&todo.Priority,
+			&todo.ParentId,
+			&todo.Position,
+			&todo.CompletedAt,
+			&todo.BoardId,
+			&todo.HasImage,
+			&todo.ChecklistDone,
+			&todo.ChecklistTotal,
+			&todo.Labels,
+			&todo.Assignees,
+			&todo.CommentCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, &todo)
+	}
+	return todos, rows.Err()
+}
+
+func (repo *TodoRepositoryPG) CountChildrenTodo(todoId int64) (int64, error) {
+	var count int64
+	sqlCount := `
+		SELECT COUNT(*)
+		FROM todos.todo
+		WHERE parent_id=$1 AND deleted_at IS NULL
+	`
+	row := repo.db.QueryRow(sqlCount, todoId)
+	if row.Err() != nil {
+		return -1, row.Err()
+	}
+	err := row.Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+	return count, nil
+}
+
+// todoHierarchyLock serializes the moves on a board, two members moving at
+// the same time could make a cycle that neither of them sees
+const todoHierarchyLock = 1401
+
+// MoveTodo puts the todo and its subtree under the parent, or on the root
+// without parent. Nothing is moved when the parent is inside the subtree.
+func (repo *TodoRepositoryPG) MoveTodo(userId int64, todo *Todo, parentId *int64) (bool, error) {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`SELECT pg_advisory_xact_lock($1, $2);`, todoHierarchyLock, todo.BoardId)
+	if err != nil {
+		return false, err
+	}
+
+	sqlUpdate := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id
+			FROM todos.todo
+			WHERE id=$3
+			UNION
+			SELECT t.id, t.parent_id
+			FROM todos.todo t
+			INNER JOIN ancestors a ON t.id = a.parent_id
+		)
+		UPDATE todos.todo
+		SET 
+			parent_id=$3,
+			updated_at=$4
+		WHERE 
+			id=$1 AND
+			tstts_id IN (SELECT id FROM todos.todo_status WHERE board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2)) AND
+			NOT EXISTS (SELECT 1 FROM ancestors WHERE id=$1);
+	`
+	result, err := tx.Exec(sqlUpdate, todo.ID, userId, parentId, now)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+	after := *todo
+	after.ParentId = parentId
+	err = insertTodoChange(tx, userId, TodoEventMoved, todo, &after)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// DeleteTodoCascade puts the todo with its whole subtree in the trash, all
+// of them with the same deletedAt to be restored together
+func (repo *TodoRepositoryPG) DeleteTodoCascade(userId, todoId int64) (int64, error) {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	sqlDelete := `
+		WITH RECURSIVE subtree AS (
+			SELECT id
+			FROM todos.todo
+			WHERE 
+				id=$1 AND
+				deleted_at IS NULL AND
+				tstts_id IN (SELECT id FROM todos.todo_status WHERE board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2))
+			UNION
+			SELECT t.id
+			FROM todos.todo t
+			INNER JOIN subtree s ON t.parent_id = s.id
+			WHERE t.deleted_at IS NULL
+		)
+		UPDATE todos.todo t
+		SET deleted_at=$3
+		FROM todos.todo_status ts
+		WHERE 
+			ts.id = t.tstts_id AND
+			t.id IN (SELECT id FROM subtree)
+		RETURNING ` + todoEventColumns + `;
+	`
+	rows, err := tx.Query(sqlDelete, todoId, userId, now)
+	if err != nil {
+		return 0, err
+	}
+	todos, err := scanTodoEventRows(rows)
+	if err != nil {
+		return 0, err
+	}
+	for _, todo := range todos {
+		err = insertTodoChange(tx, userId, TodoEventDeleted, todo, nil)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(todos)), tx.Commit()
+}
+
+// DeleteTodoReparent puts the todo in the trash and moves its children to
+// its parent, the children have their move in the history
+func (repo *TodoRepositoryPG) DeleteTodoReparent(userId, todoId int64) error {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlUpdate := `
+		UPDATE todos.todo t
+		SET 
+			parent_id=(SELECT parent_id FROM todos.todo WHERE id=$1),
+			updated_at=$3
+		FROM todos.todo_status ts
+		WHERE 
+			ts.id = t.tstts_id AND
+			t.parent_id=$1 AND
+			t.deleted_at IS NULL AND
+			ts.board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2)
+		RETURNING ` + todoEventColumns + `;
+	`
+	rows, err := tx.Query(sqlUpdate, todoId, userId, now)
+	if err != nil {
+		return err
+	}
+	children, err := scanTodoEventRows(rows)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		before := *child
+		before.ParentId = &todoId
+		err = insertTodoChange(tx, userId, TodoEventMoved, &before, child)
+		if err != nil {
+			return err
+		}
+	}
+
+	sqlDelete := `
+		UPDATE todos.todo t
+		SET deleted_at=$3
+		FROM todos.todo_status ts
+		WHERE 
+			ts.id = t.tstts_id AND
+			t.id=$1 AND
+			t.deleted_at IS NULL AND
+			ts.board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2)
+		RETURNING ` + todoEventColumns + `;
+	`
+	rows, err = tx.Query(sqlDelete, todoId, userId, now)
+	if err != nil {
+		return err
+	}
+	deleted, err := scanTodoEventRows(rows)
+	if err != nil {
+		return err
+	}
+	for _, todo := range deleted {
+		err = insertTodoChange(tx, userId, TodoEventDeleted, todo, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateImageTodo replaces the image of the todo, a nil image deletes it
+func (repo *TodoRepositoryPG) UpdateImageTodo(userId int64, todo *Todo, image *bytes.Buffer) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	var imageToArgs interface{}
 	if image == nil {
 		imageToArgs = nil
@@ -189,21 +1005,47 @@ func (repo *TodoRepositoryPG) UpdateImageTodo(todoId int64, image *bytes.Buffer)
 	sqlUpdate := `
 		UPDATE todos.todo
 		SET image=$2
-		WHERE id=$1;
+		WHERE 
+			id=$1 AND
+			tstts_id IN (SELECT id FROM todos.todo_status WHERE board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$3));
 	`
-	args := []interface{}{todoId, imageToArgs}
-	_, err := repo.db.Exec(sqlUpdate, args...)
-	return err
+	args := []interface{}{todo.ID, imageToArgs, userId}
+	result, err := tx.Exec(sqlUpdate, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		action := TodoEventImageUpdated
+		if image == nil {
+			action = TodoEventImageDeleted
+		}
+		after := *todo
+		after.Image = bytes.Buffer{}
+		after.HasImage = image != nil
+		err = insertTodoChange(tx, userId, action, todo, &after)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (repo *TodoRepositoryPG) GetImageTodo(todoId int64) (*bytes.Buffer, error) {
+func (repo *TodoRepositoryPG) GetImageTodo(userId, todoId int64) (*bytes.Buffer, error) {
 	buffImage := []byte{}
 	sqlGet := `
-		SELECT image 
-		FROM todos.todo
-		WHERE id=$1;
+		SELECT t.image 
+		FROM todos.todo t
+		INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+		WHERE 
+			t.id=$1 AND
+			t.deleted_at IS NULL AND
+			ts.board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2);
 	`
-	row := repo.db.QueryRow(sqlGet, todoId)
+	row := repo.db.QueryRow(sqlGet, todoId, userId)
 	if row.Err() != nil {
 		return nil, row.Err()
 	}
@@ -217,50 +1059,115 @@ func (repo *TodoRepositoryPG) GetImageTodo(todoId int64) (*bytes.Buffer, error)
 	return bytes.NewBuffer(buffImage), nil
 }
 
-func (repo *TodoRepositoryPG) InsertStatusTodo(name string, userId int64) (*StatusTodo, error) {
+// InsertStatusTodo adds the status as the last column of the board, the
+// board is locked so two new statuses don't get the same position
+func (repo *TodoRepositoryPG) InsertStatusTodo(name string, userId, boardId int64, color string, wipLimit *int64, category StatusCategory) (*StatusTodo, error) {
 	var statusTodo StatusTodo
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = lockBoard(tx, boardId)
+	if err != nil {
+		return nil, err
+	}
+
 	sqlInsert := `
-		INSERT INTO todos.todo_status (name, user_id)
-		VALUES ($1, $2)
-		RETURNING id, created_at, updated_at;
+		INSERT INTO todos.todo_status (name, user_id, board_id, color, wip_limit, category, position)
+		SELECT $1, $2, $6, $3, $4, $5, COALESCE(MAX(position), 0) + 1
+		FROM todos.todo_status
+		WHERE board_id=$6
+		RETURNING id, position, created_at, updated_at;
 	`
-	args := []interface{}{name, userId}
-	row := repo.db.QueryRow(sqlInsert, args...)
+	args := []interface{}{name, userId, color, wipLimit, category, boardId}
+	row := tx.QueryRow(sqlInsert, args...)
 	if row.Err() != nil {
 		return nil, row.Err()
 	}
-	row.Scan(
+	err = row.Scan(
 		&statusTodo.ID,
+		&statusTodo.Position,
 		&statusTodo.CreatedAt,
 		&statusTodo.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
 	statusTodo.Name = name
 	statusTodo.UserId = userId
+	statusTodo.BoardId = boardId
+	statusTodo.Color = color
+	statusTodo.WipLimit = wipLimit
+	statusTodo.Category = category
 	return &statusTodo, nil
 }
 
-func (repo *TodoRepositoryPG) UpdateStatusTodo(statusId int64, name string, userId int64) error {
+func (repo *TodoRepositoryPG) UpdateStatusTodo(statusId int64, name string, userId int64, color string, wipLimit *int64, category StatusCategory) error {
+	now := time.Now().UTC()
 	sqlUpdate := `
 		UPDATE todos.todo_status
 		SET 
 			name=$3,
-			user_id=$2
+			color=$4,
+			wip_limit=$5,
+			category=$7,
+			updated_at=$6
 		WHERE 
 			id=$1 AND 
-			user_id=$2; 
+			board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2);
 	`
-	args := []interface{}{statusId, userId, name}
+	args := []interface{}{statusId, userId, name, color, wipLimit, now, category}
 	_, error := repo.db.Exec(sqlUpdate, args...)
 	return error
 }
 
-func (repo *TodoRepositoryPG) GetAllStatusTodo() ([]*StatusTodo, error) {
+// ReorderStatusTodo sets the position of each status from its index on statusIds
+func (repo *TodoRepositoryPG) ReorderStatusTodo(userId int64, statusIds []int64) error {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlUpdate := `
+		UPDATE todos.todo_status
+		SET 
+			position=$3,
+			updated_at=$4
+		WHERE 
+			id=$2 AND
+			board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$1);
+	`
+	for index, statusId := range statusIds {
+		_, err = tx.Exec(sqlUpdate, userId, statusId, index+1, now)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetAllStatusTodo returns the statuses of the board in column order, every
+// board of the user without boardId
+func (repo *TodoRepositoryPG) GetAllStatusTodo(userId, boardId int64) ([]*StatusTodo, error) {
 	var allStatusTodo = make([]*StatusTodo, 0)
 	sqlGet := `
-		SELECT id, name, user_id, created_at, updated_at 
-		FROM todos.todo_status;
+		SELECT id, name, COALESCE(user_id, 0), board_id, position, color, wip_limit, category, created_at, updated_at 
+		FROM todos.todo_status
+		WHERE 
+			board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$1) AND
+			($2=0 OR board_id=$2) AND
+			deleted_at IS NULL
+		ORDER BY board_id, position, id;
 	`
-	rows, err := repo.db.Query(sqlGet)
+	rows, err := repo.db.Query(sqlGet, userId, boardId)
 	if err != nil {
 		return nil, err
 	}
@@ -270,6 +1177,11 @@ func (repo *TodoRepositoryPG) GetAllStatusTodo() ([]*StatusTodo, error) {
 			&statusTodo.ID,
 			&statusTodo.Name,
 			&statusTodo.UserId,
+			&statusTodo.BoardId,
+			&statusTodo.Position,
+			&statusTodo.Color,
+			&statusTodo.WipLimit,
+			&statusTodo.Category,
 			&statusTodo.CreatedAt,
 			&statusTodo.UpdatedAt,
 		)
@@ -284,11 +1196,13 @@ func (repo *TodoRepositoryPG) GetAllStatusTodo() ([]*StatusTodo, error) {
 func (repo *TodoRepositoryPG) GetStatusTodo(userId int64, statusID int64) (*StatusTodo, error) {
 	var statusTodo StatusTodo
 	sqlGet := `
-		SELECT id, name, user_id, created_at, updated_at
+		SELECT ts.id, ts.name, COALESCE(ts.user_id, 0), ts.board_id, bm.role, ts.position, ts.color, ts.wip_limit, ts.category, ts.created_at, ts.updated_at
 		FROM todos.todo_status ts
+		INNER JOIN todos.board_member bm ON bm.board_id = ts.board_id
 		WHERE 
-			id=$1 AND
-			user_id=$2;
+			ts.id=$1 AND
+			ts.deleted_at IS NULL AND
+			bm.user_id=$2;
 	`
 	row := repo.db.QueryRow(sqlGet, statusID, userId)
 	if row.Err() != nil {
@@ -298,6 +1212,12 @@ func (repo *TodoRepositoryPG) GetStatusTodo(userId int64, statusID int64) (*Stat
 		&statusTodo.ID,
 		&statusTodo.Name,
 		&statusTodo.UserId,
+		&statusTodo.BoardId,
+		&statusTodo.Role,
+		&statusTodo.Position,
+		&statusTodo.Color,
+		&statusTodo.WipLimit,
+		&statusTodo.Category,
 		&statusTodo.CreatedAt,
 		&statusTodo.UpdatedAt,
 	)
@@ -310,18 +1230,21 @@ func (repo *TodoRepositoryPG) GetStatusTodo(userId int64, statusID int64) (*Stat
 	return &statusTodo, nil
 }
 
-func (repo *TodoRepositoryPG) GetStatusTodoByName(userId int64, name string) (*StatusTodo, error) {
+// GetStatusTodoByName finds the status on the board without case
+func (repo *TodoRepositoryPG) GetStatusTodoByName(userId, boardId int64, name string) (*StatusTodo, error) {
 	var statusTodo StatusTodo
 	sqlGet := `
-		SELECT id, name, user_id, created_at, updated_at
+		SELECT id, name, COALESCE(user_id, 0), board_id, position, color, wip_limit, category, created_at, updated_at
 		FROM todos.todo_status ts
 		WHERE 
 			LOWER(name)=$1 AND
-			ts.user_id=$2;
+			ts.board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2) AND
+			ts.board_id=$3 AND
+			ts.deleted_at IS NULL;
 	`
 
 	nameLower := strings.ToLower(name)
-	row := repo.db.QueryRow(sqlGet, nameLower, userId)
+	row := repo.db.QueryRow(sqlGet, nameLower, userId, boardId)
 	if row.Err() != nil {
 		return nil, row.Err()
 	}
@@ -330,6 +1253,11 @@ func (repo *TodoRepositoryPG) GetStatusTodoByName(userId int64, name string) (*S
 		&statusTodo.ID,
 		&statusTodo.Name,
 		&statusTodo.UserId,
+		&statusTodo.BoardId,
+		&statusTodo.Position,
+		&statusTodo.Color,
+		&statusTodo.WipLimit,
+		&statusTodo.Category,
 		&statusTodo.CreatedAt,
 		&statusTodo.UpdatedAt,
 	)
@@ -343,12 +1271,260 @@ func (repo *TodoRepositoryPG) GetStatusTodoByName(userId int64, name string) (*S
 	return &statusTodo, nil
 }
 
+// DeleteStatusTodo puts the status in the trash, the todos already in the
+// trash stay on it
 func (repo *TodoRepositoryPG) DeleteStatusTodo(userId int64, statusID int64) error {
+	now := time.Now().UTC()
 	sqlDelete := `
-		DELETE FROM todos.todo_status
-		WHERE id=$1 AND user_id=$2;
+		UPDATE todos.todo_status
+		SET deleted_at=$3
+		WHERE id=$1 AND deleted_at IS NULL AND board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2);
 	`
-	args := []interface{}{statusID, userId}
+	args := []interface{}{statusID, userId, now}
 	_, error := repo.db.Exec(sqlDelete, args...)
 	return error
 }
+
+// DeleteStatusTodoMovingTodos moves the todos of the status to the end of the
+// other status, in their order, and deletes the status in one transaction.
+// The wip limit of the other status is checked, the transitions aren't, the
+// completedAt follows its category.
+func (repo *TodoRepositoryPG) DeleteStatusTodoMovingTodos(userId, statusId, toStatusId int64) (int64, error) {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// always in the same order, two opposite moves can't wait for each other
+	firstStatusId, secondStatusId := statusId, toStatusId
+	if firstStatusId > secondStatusId {
+		firstStatusId, secondStatusId = secondStatusId, firstStatusId
+	}
+	err = lockStatusTodo(tx, firstStatusId)
+	if err != nil {
+		return 0, err
+	}
+	err = lockStatusTodo(tx, secondStatusId)
+	if err != nil {
+		return 0, err
+	}
+
+	todoIds := make([]int64, 0)
+	sqlGet := `
+		SELECT id
+		FROM todos.todo
+		WHERE tstts_id=$1 AND deleted_at IS NULL
+		ORDER BY position, id;
+	`
+	rows, err := tx.Query(sqlGet, statusId)
+	if err != nil {
+		return 0, err
+	}
+	for rows.Next() {
+		var todoId int64
+		err = rows.Scan(&todoId)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		todoIds = append(todoIds, todoId)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var wipLimit sql.NullInt64
+	var count int64
+	sqlCheck := `
+		SELECT ts.wip_limit, (SELECT COUNT(*) FROM todos.todo WHERE tstts_id=ts.id AND deleted_at IS NULL)
+		FROM todos.todo_status ts
+		WHERE ts.id=$1;
+	`
+	err = tx.QueryRow(sqlCheck, toStatusId).Scan(&wipLimit, &count)
+	if err != nil {
+		return 0, err
+	}
+	if wipLimit.Valid && count+int64(len(todoIds)) > wipLimit.Int64 {
+		return 0, ErrStatusWipLimitReached
+	}
+
+	var boardId int64
+	err = tx.QueryRow(`SELECT board_id FROM todos.todo_status WHERE id=$1;`, toStatusId).Scan(&boardId)
+	if err != nil {
+		return 0, err
+	}
+	position, err := lastTodoPosition(tx, toStatusId, 0)
+	if err != nil {
+		return 0, err
+	}
+	// the todo before the update is on the old row of the FROM
+	sqlUpdate := `
+		UPDATE todos.todo t
+		SET 
+			tstts_id=$2,
+			position=$3,
+			updated_at=$4,
+			completed_at=CASE
+				WHEN (SELECT category FROM todos.todo_status WHERE id=$2)='done' THEN COALESCE(t.completed_at, $4)
+				ELSE NULL
+			END
+		FROM (SELECT id, tstts_id, position, completed_at FROM todos.todo WHERE id=$1) old
+		WHERE t.id=old.id
+		RETURNING old.tstts_id, old.position, old.completed_at, t.completed_at;
+	`
+	for _, todoId := range todoIds {
+		before := Todo{ID: todoId, BoardId: boardId}
+		after := Todo{ID: todoId, BoardId: boardId, StatusID: toStatusId, Position: position}
+		err = tx.QueryRow(sqlUpdate, todoId, toStatusId, position, now).Scan(&before.StatusID, &before.Position, &before.CompletedAt, &after.CompletedAt)
+		if err != nil {
+			return 0, err
+		}
+		err = insertTodoEvent(tx, todoId, boardId, userId, TodoEventMoved, DiffTodo(&before, &after))
+		if err != nil {
+			return 0, err
+		}
+		position, err = RankBetween(position, "")
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	sqlDelete := `
+		UPDATE todos.todo_status
+		SET deleted_at=$3
+		WHERE id=$1 AND deleted_at IS NULL AND board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2);
+	`
+	_, err = tx.Exec(sqlDelete, statusId, userId, now)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(todoIds)), tx.Commit()
+}
+
+// DeleteStatusTodoCascade puts the status with its todos in the trash, all of
+// them with the same deletedAt. Their children on other statuses lose their
+// parent.
+func (repo *TodoRepositoryPG) DeleteStatusTodoCascade(userId, statusId int64) (int64, error) {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	err = lockStatusTodo(tx, statusId)
+	if err != nil {
+		return 0, err
+	}
+
+	sqlDeleteTodos := `
+		UPDATE todos.todo t
+		SET deleted_at=$3
+		FROM todos.todo_status ts
+		WHERE 
+			ts.id = t.tstts_id AND
+			ts.id=$1 AND
+			t.deleted_at IS NULL AND
+			ts.board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2)
+		RETURNING ` + todoEventColumns + `;
+	`
+	rows, err := tx.Query(sqlDeleteTodos, statusId, userId, now)
+	if err != nil {
+		return 0, err
+	}
+	todos, err := scanTodoEventRows(rows)
+	if err != nil {
+		return 0, err
+	}
+	for _, todo := range todos {
+		err = insertTodoEvent(tx, todo.ID, todo.BoardId, userId, TodoEventDeleted, DiffTodo(todo, nil))
+		if err != nil {
+			return 0, err
+		}
+	}
+	deleted := int64(len(todos))
+
+	sqlOrphans := `
+		UPDATE todos.todo
+		SET 
+			parent_id=NULL,
+			updated_at=$2
+		WHERE 
+			deleted_at IS NULL AND
+			parent_id IN (SELECT id FROM todos.todo WHERE tstts_id=$1 AND deleted_at=$2);
+	`
+	_, err = tx.Exec(sqlOrphans, statusId, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sqlDelete := `
+		UPDATE todos.todo_status
+		SET deleted_at=$3
+		WHERE id=$1 AND deleted_at IS NULL AND board_id IN (SELECT board_id FROM todos.board_member WHERE user_id=$2);
+	`
+	_, err = tx.Exec(sqlDelete, statusId, userId, now)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, tx.Commit()
+}
+
+// GetStatusTransitions returns the statuses allowed after the status,
+// none means every status is allowed
+func (repo *TodoRepositoryPG) GetStatusTransitions(statusId int64) ([]int64, error) {
+	var toStatusIds = make([]int64, 0)
+	sqlGet := `
+		SELECT st.to_status_id
+		FROM todos.status_transition st
+		INNER JOIN todos.todo_status ts ON ts.id = st.to_status_id
+		WHERE st.from_status_id=$1 AND ts.deleted_at IS NULL
+		ORDER BY ts.position, ts.id;
+	`
+	rows, err := repo.db.Query(sqlGet, statusId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var toStatusId int64
+		err := rows.Scan(&toStatusId)
+		if err != nil {
+			return nil, err
+		}
+		toStatusIds = append(toStatusIds, toStatusId)
+	}
+	return toStatusIds, rows.Err()
+}
+
+func (repo *TodoRepositoryPG) ReplaceStatusTransitions(statusId int64, toStatusIds []int64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlDelete := `
+		DELETE FROM todos.status_transition
+		WHERE from_status_id=$1;
+	`
+	_, err = tx.Exec(sqlDelete, statusId)
+	if err != nil {
+		return err
+	}
+
+	sqlInsert := `
+		INSERT INTO todos.status_transition (from_status_id, to_status_id)
+		SELECT $1, UNNEST($2::int[])
+		ON CONFLICT DO NOTHING;
+	`
+	_, err = tx.Exec(sqlInsert, statusId, pq.Array(toStatusIds))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,97 @@
+package todos
+
+import (
+	"api/modules/users/middlewares"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashController interface {
+	GetAllTrash() func(c *gin.Context)
+	RestoreTrash() func(c *gin.Context)
+}
+
+type TrashControllerGin struct {
+	trashUsecase TrashUsecase
+}
+
+func NewTrashController(trashUsecase TrashUsecase) TrashController {
+	return &TrashControllerGin{trashUsecase}
+}
+
+func (controller *TrashControllerGin) GetAllTrash() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		var boardId int64
+		if boardIdStr := c.Query("boardId"); boardIdStr != "" {
+			var err error
+			boardId, err = strconv.ParseInt(boardIdStr, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "boardId should be an integer"})
+				return
+			}
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for get all trash")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		items, usecaseErr, serverErr := controller.trashUsecase.GetAllTrash(userId, boardId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func (controller *TrashControllerGin) RestoreTrash() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		kind := TrashKind(c.Param("kind"))
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing item id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing item id integer on url param"})
+			return
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for restore trash")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		restore, usecaseErr, serverErr := controller.trashUsecase.RestoreTrash(userId, kind, id)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(usecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, restore)
+	}
+}
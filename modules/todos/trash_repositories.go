@@ -0,0 +1,396 @@
+package todos
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type TrashRepository interface {
+	GetAllTrash(userId, boardId int64) ([]*TrashItem, error)
+	GetTrashItem(userId int64, kind TrashKind, id int64) (*TrashItem, error)
+	RestoreTodo(userId, todoId int64) (*TrashRestore, error)
+	RestoreStatus(userId, statusId int64) (*TrashRestore, error)
+	PurgeTrash(deletedBefore time.Time) (int64, error)
+}
+
+type TrashRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewTrashRepository(db *sql.DB) TrashRepository {
+	return &TrashRepositoryPG{db}
+}
+
+// trashItemsQuery lists the statuses and the todos in the trash of the
+// boards of the user $1, filtered by the where clause
+const trashItemsQuery = `
+	SELECT kind, id, name, board_id, status_id, role, deleted_at
+	FROM (
+		SELECT 'status' AS kind, ts.id, ts.name, ts.board_id, NULL::int AS status_id, bm.role, ts.deleted_at
+		FROM todos.todo_status ts
+		INNER JOIN todos.board_member bm ON bm.board_id = ts.board_id
+		WHERE 
+			ts.deleted_at IS NOT NULL AND
+			bm.user_id=$1
+		UNION ALL
+		SELECT 'todo', t.id, t.title, ts.board_id, t.tstts_id, bm.role, t.deleted_at
+		FROM todos.todo t
+		INNER JOIN todos.todo_status ts ON ts.id = t.tstts_id
+		INNER JOIN todos.board_member bm ON bm.board_id = ts.board_id
+		WHERE 
+			t.deleted_at IS NOT NULL AND
+			bm.user_id=$1
+	) trash
+	WHERE %s
+	ORDER BY deleted_at DESC, kind, id;
+`
+
+func (repo *TrashRepositoryPG) getTrash(where string, args ...interface{}) ([]*TrashItem, error) {
+	var items = make([]*TrashItem, 0)
+	rows, err := repo.db.Query(fmt.Sprintf(trashItemsQuery, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item TrashItem
+		err = rows.Scan(
+			&item.Kind,
+			&item.ID,
+			&item.Name,
+			&item.BoardId,
+			&item.StatusId,
+			&item.Role,
+			&item.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+
+// GetAllTrash returns the trash of the board from the last deleted, every
+// board of the user without boardId
+func (repo *TrashRepositoryPG) GetAllTrash(userId, boardId int64) ([]*TrashItem, error) {
+	return repo.getTrash("($2=0 OR board_id=$2)", userId, boardId)
+}
+
+func (repo *TrashRepositoryPG) GetTrashItem(userId int64, kind TrashKind, id int64) (*TrashItem, error) {
+	items, err := repo.getTrash("kind=$2 AND id=$3", userId, kind, id)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+// restoreTrashStatus takes the status out of the trash as the last column
+// of its board and returns when it was deleted, nil when it wasn't. A name
+// already taken on the board returns ErrTrashStatusNameTaken. Its board in
+// the trash comes back too.
+func restoreTrashStatus(tx *sql.Tx, statusId int64, now time.Time) (*time.Time, error) {
+	var boardId int64
+	var name string
+	var deletedAt *time.Time
+	sqlGet := `
+		SELECT board_id, name, deleted_at
+		FROM todos.todo_status
+		WHERE id=$1
+		FOR UPDATE;
+	`
+	err := tx.QueryRow(sqlGet, statusId).Scan(&boardId, &name, &deletedAt)
+	if err != nil || deletedAt == nil {
+		return nil, err
+	}
+
+	var nameTaken bool
+	sqlName := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM todos.todo_status
+			WHERE 
+				board_id=$1 AND
+				LOWER(name)=LOWER($2) AND
+				deleted_at IS NULL
+		);
+	`
+	err = tx.QueryRow(sqlName, boardId, name).Scan(&nameTaken)
+	if err != nil {
+		return nil, err
+	}
+	if nameTaken {
+		return nil, ErrTrashStatusNameTaken
+	}
+
+	sqlRestore := `
+		UPDATE todos.todo_status
+		SET 
+			deleted_at=NULL,
+			updated_at=$3,
+			position=(SELECT COALESCE(MAX(position), 0) + 1 FROM todos.todo_status WHERE board_id=$2 AND deleted_at IS NULL)
+		WHERE id=$1;
+	`
+	_, err = tx.Exec(sqlRestore, statusId, boardId, now)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlRestoreBoard := `
+		UPDATE todos.board
+		SET 
+			deleted_at=NULL,
+			updated_at=$2
+		WHERE 
+			id=$1 AND
+			deleted_at IS NOT NULL;
+	`
+	_, err = tx.Exec(sqlRestoreBoard, boardId, now)
+	if err != nil {
+		return nil, err
+	}
+	return deletedAt, nil
+}
+
+// RestoreTodo takes the todo out of the trash with the subtree deleted with
+// it, each todo at the end of its column. Their statuses in the trash come
+// back too. The todo goes to the root when its parent is still in the
+// trash. The history of each todo has its restore by the user. Nil when the
+// todo isn't in the trash.
+func (repo *TrashRepositoryPG) RestoreTodo(userId, todoId int64) (*TrashRestore, error) {
+	now := time.Now().UTC()
+	restore := &TrashRestore{TodoIds: make([]int64, 0), StatusIds: make([]int64, 0)}
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var found int64
+	sqlLock := `
+		SELECT id
+		FROM todos.todo
+		WHERE id=$1 AND deleted_at IS NOT NULL
+		FOR UPDATE;
+	`
+	err = tx.QueryRow(sqlLock, todoId).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	type trashTodo struct {
+		id       int64
+		statusId int64
+		orphan   bool
+	}
+	todos := make([]trashTodo, 0)
+	sqlSubtree := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, tstts_id, parent_id, deleted_at, 1 AS depth
+			FROM todos.todo
+			WHERE id=$1
+			UNION
+			SELECT t.id, t.tstts_id, t.parent_id, t.deleted_at, s.depth + 1
+			FROM todos.todo t
+			INNER JOIN subtree s ON t.parent_id = s.id
+			WHERE t.deleted_at = s.deleted_at
+		)
+		SELECT s.id, s.tstts_id, s.depth = 1 AND EXISTS (SELECT 1 FROM todos.todo p WHERE p.id = s.parent_id AND p.deleted_at IS NOT NULL)
+		FROM subtree s
+		ORDER BY s.depth, s.id;
+	`
+	rows, err := tx.Query(sqlSubtree, todoId)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var todo trashTodo
+		err = rows.Scan(&todo.id, &todo.statusId, &todo.orphan)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sqlRestore := `
+		UPDATE todos.todo t
+		SET 
+			deleted_at=NULL,
+			position=$2,
+			parent_id=CASE WHEN $3 THEN NULL ELSE t.parent_id END,
+			updated_at=$4
+		FROM todos.todo_status ts
+		WHERE 
+			ts.id = t.tstts_id AND
+			t.id=$1
+		RETURNING ` + todoEventColumns + `;
+	`
+	statusChecked := map[int64]bool{}
+	for _, todo := range todos {
+		if !statusChecked[todo.statusId] {
+			statusChecked[todo.statusId] = true
+			deletedAt, err := restoreTrashStatus(tx, todo.statusId, now)
+			if err != nil {
+				return nil, err
+			}
+			if deletedAt != nil {
+				restore.StatusIds = append(restore.StatusIds, todo.statusId)
+			}
+		}
+
+		position, err := lastTodoPosition(tx, todo.statusId, todo.id)
+		if err != nil {
+			return nil, err
+		}
+		err = checkWipLimit(tx, todo.statusId, todo.id)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := tx.Query(sqlRestore, todo.id, position, todo.orphan, now)
+		if err != nil {
+			return nil, err
+		}
+		restored, err := scanTodoEventRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, restoredTodo := range restored {
+			err = insertTodoChange(tx, userId, TodoEventRestored, nil, restoredTodo)
+			if err != nil {
+				return nil, err
+			}
+		}
+		restore.TodoIds = append(restore.TodoIds, todo.id)
+	}
+	return restore, tx.Commit()
+}
+
+// RestoreStatus takes the status out of the trash with the todos deleted
+// with it, on their positions, each one restored by the user in its history.
+// The todos deleted before stay in the trash. Nil when the status isn't in
+// the trash.
+func (repo *TrashRepositoryPG) RestoreStatus(userId, statusId int64) (*TrashRestore, error) {
+	now := time.Now().UTC()
+	restore := &TrashRestore{TodoIds: make([]int64, 0), StatusIds: make([]int64, 0)}
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	deletedAt, err := restoreTrashStatus(tx, statusId, now)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && deletedAt == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	restore.StatusIds = append(restore.StatusIds, statusId)
+
+	// the parents deleted with the status come back with it
+	sqlRestore := `
+		UPDATE todos.todo t
+		SET 
+			deleted_at=NULL,
+			updated_at=$3,
+			parent_id=CASE
+				WHEN EXISTS (
+					SELECT 1
+					FROM todos.todo p
+					WHERE 
+						p.id = t.parent_id AND
+						p.deleted_at IS NOT NULL AND
+						NOT (p.tstts_id=$1 AND p.deleted_at=$2)
+				) THEN NULL
+				ELSE t.parent_id
+			END
+		FROM todos.todo_status ts
+		WHERE 
+			ts.id = t.tstts_id AND
+			t.tstts_id=$1 AND
+			t.deleted_at=$2
+		RETURNING ` + todoEventColumns + `;
+	`
+	rows, err := tx.Query(sqlRestore, statusId, deletedAt, now)
+	if err != nil {
+		return nil, err
+	}
+	todos, err := scanTodoEventRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, todo := range todos {
+		err = insertTodoChange(tx, userId, TodoEventRestored, nil, todo)
+		if err != nil {
+			return nil, err
+		}
+		restore.TodoIds = append(restore.TodoIds, todo.ID)
+	}
+	return restore, tx.Commit()
+}
+
+// PurgeTrash deletes for good the todos, the statuses and the boards deleted
+// before the time, a status waits for the todos still in the trash on it
+// and a board for its statuses
+func (repo *TrashRepositoryPG) PurgeTrash(deletedBefore time.Time) (int64, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	sqlDeleteTodos := `
+		DELETE FROM todos.todo
+		WHERE deleted_at < $1;
+	`
+	result, err := tx.Exec(sqlDeleteTodos, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	countTodos, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	sqlDeleteStatuses := `
+		DELETE FROM todos.todo_status ts
+		WHERE 
+			ts.deleted_at < $1 AND
+			NOT EXISTS (SELECT 1 FROM todos.todo t WHERE t.tstts_id = ts.id);
+	`
+	result, err = tx.Exec(sqlDeleteStatuses, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	countStatuses, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	sqlDeleteBoards := `
+		DELETE FROM todos.board b
+		WHERE 
+			b.deleted_at < $1 AND
+			NOT EXISTS (SELECT 1 FROM todos.todo_status ts WHERE ts.board_id = b.id);
+	`
+	result, err = tx.Exec(sqlDeleteBoards, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	countBoards, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return countTodos + countStatuses + countBoards, tx.Commit()
+}
@@ -0,0 +1,117 @@
+package todos
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type TrashUsecase interface {
+	GetAllTrash(userId, boardId int64) (items []*TrashItem, usecaseErr error, serverErr error)
+	RestoreTrash(userId int64, kind TrashKind, id int64) (restore *TrashRestore, usecaseErr error, serverErr error)
+	PurgeTrash() (int64, error)
+	StartPurge(interval time.Duration)
+}
+
+var (
+	ErrTrashItemNotFound    = errors.New("item not found in the trash")
+	ErrTrashItemIdNegative  = errors.New("item id should be positive")
+	ErrTrashStatusNameTaken = errors.New("the status can't be restored, the board has another status with its name")
+)
+
+type DBTrashUsecase struct {
+	trashRepository TrashRepository
+	// retention is how long the items stay in the trash
+	retention time.Duration
+}
+
+func NewTrashUsecase(trashRepository TrashRepository, retention time.Duration) TrashUsecase {
+	return &DBTrashUsecase{trashRepository, retention}
+}
+
+func (usecase *DBTrashUsecase) GetAllTrash(userId, boardId int64) (items []*TrashItem, usecaseErr error, serverErr error) {
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	if boardId < 0 {
+		usecaseErr = ErrBoardIdNegative
+		return
+	}
+	items, serverErr = usecase.trashRepository.GetAllTrash(userId, boardId)
+	if serverErr != nil {
+		return
+	}
+	for _, item := range items {
+		item.PurgeAt = item.DeletedAt.Add(usecase.retention)
+	}
+	return
+}
+
+// RestoreTrash takes the item out of the trash, a todo brings back its
+// status when it is in the trash too
+func (usecase *DBTrashUsecase) RestoreTrash(userId int64, kind TrashKind, id int64) (restore *TrashRestore, usecaseErr error, serverErr error) {
+	usecaseErr = kind.Valid()
+	if usecaseErr != nil {
+		return
+	}
+	if id <= 0 {
+		usecaseErr = ErrTrashItemIdNegative
+		return
+	}
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+
+	item, serverErr := usecase.trashRepository.GetTrashItem(userId, kind, id)
+	if serverErr != nil {
+		return
+	}
+	if item == nil {
+		usecaseErr = ErrTrashItemNotFound
+		return
+	}
+	if !item.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
+
+	if kind == TrashKindTodo {
+		restore, serverErr = usecase.trashRepository.RestoreTodo(userId, id)
+	} else {
+		restore, serverErr = usecase.trashRepository.RestoreStatus(userId, id)
+	}
+	if errors.Is(serverErr, ErrStatusWipLimitReached) || errors.Is(serverErr, ErrTrashStatusNameTaken) {
+		usecaseErr, serverErr = serverErr, nil
+		return
+	}
+	if serverErr != nil {
+		return
+	}
+	// restored in the meantime
+	if restore == nil {
+		usecaseErr = ErrTrashItemNotFound
+	}
+	return
+}
+
+// PurgeTrash deletes for good the items in the trash for longer than the
+// retention
+func (usecase *DBTrashUsecase) PurgeTrash() (int64, error) {
+	return usecase.trashRepository.PurgeTrash(time.Now().UTC().Add(-usecase.retention))
+}
+
+func (usecase *DBTrashUsecase) StartPurge(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			_, err := usecase.PurgeTrash()
+			if err != nil {
+				// TODO: make log
+				fmt.Println(err)
+			}
+		}
+	}()
+}
@@ -4,25 +4,33 @@ import (
 	usersUsecase "api/modules/users/usecases"
 	"bytes"
 	"errors"
+	"time"
 )
 
 type TodoUsecase interface {
 	// TODO: Mudar parâmetros de todas funções para dto (data transfer object)
-	CreateTodo(title, description string, statusTodoId, userId int64) (todo *Todo, usecaseErr error, serverErr error)
-	UpdateTodo(todoID int64, title, description string, statusTodoId, userId int64) (usecaseErr error, serverErr error)
-	DeleteTodo(todoID int64) (usecaseErr error, serverErr error)
-	GetTodo(todoID int64) (todo *Todo, usecaseErr error, serverErr error)
-	GetAllTodo() (todos []*Todo, usecaseErr error, serverErr error)
-
-	UpdateImageTodo(dto *UpdateImageTodoDTO) (usecaseErr error, serverErr error)
-	GetImageTodo(todoID int64) (image *bytes.Buffer, usecaseErr error, serverErr error)
-	DeleteImageTodo(todoID int64) (usecaseErr error, serverErr error)
-
-	CreateStatusTodo(name string, userId int64) (statusTodo *StatusTodo, usecaseErr error, serverErr error)
-	UpdateStatusTodo(userId int64, statusTodoId int64, name string) (usecaseErr error, serverErr error)
+	CreateTodo(title, description string, statusTodoId, userId int64, startAt, dueAt *time.Time, priority Priority, parentId *int64) (todo *Todo, usecaseErr error, serverErr error)
+	UpdateTodo(todoID int64, title, description string, statusTodoId, userId int64, startAt, dueAt *time.Time, priority Priority) (usecaseErr error, serverErr error)
+	DeleteTodo(userId, todoID int64, childrenMode ChildrenDeleteMode) (deleted int64, usecaseErr error, serverErr error)
+	GetTodo(userId, todoID int64) (todo *Todo, usecaseErr error, serverErr error)
+	GetAllTodo(query TodoQuery) (page *TodoPage, usecaseErr error, serverErr error)
+
+	GetChildrenTodo(userId, todoId int64, recursive bool) (children []*Todo, usecaseErr error, serverErr error)
+	MoveTodo(userId, todoId int64, parentId *int64) (usecaseErr error, serverErr error)
+	PositionTodo(userId, todoId, statusId int64, previousId, nextId *int64) (todo *Todo, usecaseErr error, serverErr error)
+
+	UpdateImageTodo(userId int64, dto *UpdateImageTodoDTO) (usecaseErr error, serverErr error)
+	GetImageTodo(userId, todoID int64) (image *bytes.Buffer, usecaseErr error, serverErr error)
+	DeleteImageTodo(userId, todoID int64) (usecaseErr error, serverErr error)
+
+	CreateStatusTodo(name string, userId, boardId int64, color string, wipLimit *int64, category StatusCategory) (statusTodo *StatusTodo, usecaseErr error, serverErr error)
+	UpdateStatusTodo(userId int64, statusTodoId int64, name, color string, wipLimit *int64, category StatusCategory) (usecaseErr error, serverErr error)
+	ReorderStatusTodo(userId, boardId int64, statusIds []int64) (allStatusTodo []*StatusTodo, usecaseErr error, serverErr error)
 	GetStatusTodo(userId, id int64) (statusTodo *StatusTodo, usecaseErr error, serverErr error)
-	GetAllStatusTodo() (allStatusTodo []*StatusTodo, usecaseErr error, serverErr error)
-	DeleteStatusTodo(userId, statusId int64) (usecaseErr error, serverErr error)
+	GetAllStatusTodo(userId, boardId int64) (allStatusTodo []*StatusTodo, usecaseErr error, serverErr error)
+	DeleteStatusTodo(userId, statusId, moveToStatusId int64, cascade bool) (affected int64, usecaseErr error, serverErr error)
+	GetStatusTransitions(userId, statusId int64) (toStatus []*StatusTodo, usecaseErr error, serverErr error)
+	SetStatusTransitions(userId, statusId int64, toStatusIds []int64) (toStatus []*StatusTodo, usecaseErr error, serverErr error)
 }
 
 var (
@@ -38,23 +46,49 @@ var (
 	ErrTodoIdIsNegative        = errors.New("todo id should be positive")
 	ErrHasTodosWithStatusId    = errors.New("essa lista tem alguns Item, remove-os antes")
 	ErrImageNotFound           = errors.New("image not found")
+	ErrStartAtAfterDueAt       = errors.New("startAt should be before dueAt")
+	ErrParentTodoNotFound      = errors.New("parent todo not found")
+	ErrTodoParentCycle         = errors.New("todo can't be moved under itself or one of its children")
+	ErrTodoHasChildren         = errors.New("todo has children, delete with children=cascade or children=reparent")
+	ErrChildrenModeInvalid     = errors.New("children should be cascade or reparent")
+	ErrTodoNeighborNotFound    = errors.New("neighbor todo not found on the status")
+	ErrTodoMoveConflict        = errors.New("the column changed, reload it and move again")
+	ErrStatusWipLimitReached   = errors.New("status has reached its wip limit")
+	ErrStatusWipLimitInvalid   = errors.New("wip limit should be positive")
+	ErrStatusOrderInvalid      = errors.New("status order should have every status id once")
+	ErrStatusTransitionDenied  = errors.New("todo can't move from its status to this status")
+	ErrStatusTransitionInvalid = errors.New("transitions should go to other statuses of the board")
+	ErrStatusDeleteModeInvalid = errors.New("delete the status with moveTo or cascade, not both")
+	ErrMoveToStatusInvalid     = errors.New("moveTo should be another status of the board")
+	ErrStatusOtherBoard        = errors.New("status should be on the board of the todo")
+	ErrParentTodoOtherBoard    = errors.New("parent todo should be on the same board")
 )
 
 type DBTodoUsecase struct {
-	todoRepository TodoRepository
-	userRepository usersUsecase.UserUsecase
+	todoRepository  TodoRepository
+	userRepository  usersUsecase.UserUsecase
+	boardRepository BoardRepository
 }
 
 func NewTodoUsecase(
 	todoRepository TodoRepository,
 	userRepository usersUsecase.UserUsecase,
+	boardRepository BoardRepository,
 ) TodoUsecase {
-	return &DBTodoUsecase{todoRepository, userRepository}
+	return &DBTodoUsecase{todoRepository, userRepository, boardRepository}
 }
 
-func (usecase *DBTodoUsecase) CreateTodo(title, description string, statusTodoId, userId int64) (todo *Todo, usecaseErr error, serverErr error) {
+func (usecase *DBTodoUsecase) CreateTodo(title, description string, statusTodoId, userId int64, startAt, dueAt *time.Time, priority Priority, parentId *int64) (todo *Todo, usecaseErr error, serverErr error) {
 	// TODO: mover validação para o model
 
+	if startAt != nil && dueAt != nil && startAt.After(*dueAt) {
+		usecaseErr = ErrStartAtAfterDueAt
+		return
+	}
+	usecaseErr = priority.Valid()
+	if usecaseErr != nil {
+		return
+	}
 	if len(title) > 255 {
 		usecaseErr = ErrTitleIsLong
 		return
@@ -77,8 +111,38 @@ func (usecase *DBTodoUsecase) CreateTodo(title, description string, statusTodoId
 		usecaseErr = ErrStatusTodoNotFound
 		return
 	}
+	if !statusFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
+
+	if parentId != nil {
+		parentFound, err := usecase.todoRepository.GetTodo(userId, *parentId)
+		if err != nil {
+			serverErr = err
+			return
+		}
+		if parentFound == nil {
+			usecaseErr = ErrParentTodoNotFound
+			return
+		}
+		if parentFound.BoardId != statusFound.BoardId {
+			usecaseErr = ErrParentTodoOtherBoard
+			return
+		}
+	}
+
+	var completedAt *time.Time
+	if statusFound.Category == StatusCategoryDone {
+		now := time.Now().UTC()
+		completedAt = &now
+	}
 
-	todo, err = usecase.todoRepository.InsertTodo(title, description, statusTodoId)
+	todo, err = usecase.todoRepository.InsertTodo(userId, title, description, statusTodoId, startAt, dueAt, priority, parentId, completedAt)
+	if errors.Is(err, ErrStatusWipLimitReached) {
+		usecaseErr = err
+		return
+	}
 	if err != nil {
 		serverErr = err
 		return
@@ -87,9 +151,17 @@ func (usecase *DBTodoUsecase) CreateTodo(title, description string, statusTodoId
 	return
 }
 
-func (usecase *DBTodoUsecase) UpdateTodo(todoId int64, title, description string, statusTodoId, userId int64) (usecaseErr error, serverErr error) {
+func (usecase *DBTodoUsecase) UpdateTodo(todoId int64, title, description string, statusTodoId, userId int64, startAt, dueAt *time.Time, priority Priority) (usecaseErr error, serverErr error) {
 
 	// TODO: mover validação para o model
+	if startAt != nil && dueAt != nil && startAt.After(*dueAt) {
+		usecaseErr = ErrStartAtAfterDueAt
+		return
+	}
+	usecaseErr = priority.Valid()
+	if usecaseErr != nil {
+		return
+	}
 	if statusTodoId <= 0 {
 		usecaseErr = ErrStatusTodoIdNegative
 		return
@@ -121,7 +193,7 @@ func (usecase *DBTodoUsecase) UpdateTodo(todoId int64, title, description string
 		return
 	}
 
-	todoFound, err := usecase.todoRepository.GetTodo(todoId)
+	todoFound, err := usecase.todoRepository.GetTodo(userId, todoId)
 	if err != nil {
 		serverErr = err
 		return
@@ -130,8 +202,21 @@ func (usecase *DBTodoUsecase) UpdateTodo(todoId int64, title, description string
 		usecaseErr = ErrTodoNotFound
 		return
 	}
+	if !todoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
 
-	err = usecase.todoRepository.UpdateTodo(todoId, title, description, statusTodoId)
+	completedAt, usecaseErr, serverErr := usecase.transitionTodo(todoFound, statusFound)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	err = usecase.todoRepository.UpdateTodo(userId, todoFound, title, description, statusTodoId, startAt, dueAt, priority, completedAt)
+	if errors.Is(err, ErrStatusWipLimitReached) {
+		usecaseErr = err
+		return
+	}
 	if err != nil {
 		serverErr = err
 		return
@@ -139,13 +224,61 @@ func (usecase *DBTodoUsecase) UpdateTodo(todoId int64, title, description string
 	return
 }
 
-func (usecase *DBTodoUsecase) DeleteTodo(todoID int64) (usecaseErr error, serverErr error) {
+// DeleteTodo needs the children mode when the todo has children,
+// deleted counts the todos deleted with the subtree
+func (usecase *DBTodoUsecase) DeleteTodo(userId, todoID int64, childrenMode ChildrenDeleteMode) (deleted int64, usecaseErr error, serverErr error) {
 	if todoID <= 0 {
 		usecaseErr = ErrTodoIdIsNegative
 		return
 	}
+	if childrenMode != "" && childrenMode != ChildrenDeleteCascade && childrenMode != ChildrenDeleteReparent {
+		usecaseErr = ErrChildrenModeInvalid
+		return
+	}
+
+	todoFound, usecaseErr, serverErr := usecase.GetTodo(userId, todoID)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if todoFound == nil {
+		usecaseErr = ErrTodoNotFound
+		return
+	}
+	if !todoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
 
-	todoFound, usecaseErr, serverErr := usecase.GetTodo(todoID)
+	switch childrenMode {
+	case ChildrenDeleteCascade:
+		deleted, serverErr = usecase.todoRepository.DeleteTodoCascade(userId, todoID)
+		return
+	case ChildrenDeleteReparent:
+		serverErr = usecase.todoRepository.DeleteTodoReparent(userId, todoID)
+		if serverErr == nil {
+			deleted = 1
+		}
+		return
+	}
+
+	children, err := usecase.todoRepository.CountChildrenTodo(todoID)
+	if err != nil {
+		serverErr = err
+		return
+	}
+	if children > 0 {
+		usecaseErr = ErrTodoHasChildren
+		return
+	}
+	serverErr = usecase.todoRepository.DeleteTodo(userId, todoFound)
+	if serverErr == nil {
+		deleted = 1
+	}
+	return
+}
+
+func (usecase *DBTodoUsecase) GetChildrenTodo(userId, todoId int64, recursive bool) (children []*Todo, usecaseErr error, serverErr error) {
+	todoFound, usecaseErr, serverErr := usecase.GetTodo(userId, todoId)
 	if usecaseErr != nil || serverErr != nil {
 		return
 	}
@@ -153,26 +286,208 @@ func (usecase *DBTodoUsecase) DeleteTodo(todoID int64) (usecaseErr error, server
 		usecaseErr = ErrTodoNotFound
 		return
 	}
-	serverErr = usecase.todoRepository.DeleteTodo(todoID)
+	children, serverErr = usecase.todoRepository.GetChildrenTodo(userId, todoId, recursive)
 	return
 }
 
-func (usecase *DBTodoUsecase) GetTodo(todoID int64) (todo *Todo, usecaseErr error, serverErr error) {
+// MoveTodo moves the todo with its subtree under the parent, a nil parent
+// moves it to the root
+func (usecase *DBTodoUsecase) MoveTodo(userId, todoId int64, parentId *int64) (usecaseErr error, serverErr error) {
+	todoFound, usecaseErr, serverErr := usecase.GetTodo(userId, todoId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if todoFound == nil {
+		usecaseErr = ErrTodoNotFound
+		return
+	}
+	if !todoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
+
+	if parentId != nil {
+		if *parentId == todoId {
+			usecaseErr = ErrTodoParentCycle
+			return
+		}
+		parentFound, err := usecase.todoRepository.GetTodo(userId, *parentId)
+		if err != nil {
+			serverErr = err
+			return
+		}
+		if parentFound == nil {
+			usecaseErr = ErrParentTodoNotFound
+			return
+		}
+		if parentFound.BoardId != todoFound.BoardId {
+			usecaseErr = ErrParentTodoOtherBoard
+			return
+		}
+	}
+
+	moved, err := usecase.todoRepository.MoveTodo(userId, todoFound, parentId)
+	if err != nil {
+		serverErr = err
+		return
+	}
+	if !moved {
+		usecaseErr = ErrTodoParentCycle
+	}
+	return
+}
+
+// PositionTodo moves the todo to the status, between the previous and the
+// next todos of the column
+func (usecase *DBTodoUsecase) PositionTodo(userId, todoId, statusId int64, previousId, nextId *int64) (todo *Todo, usecaseErr error, serverErr error) {
+	if statusId <= 0 {
+		usecaseErr = ErrStatusTodoIdNegative
+		return
+	}
+	todo, usecaseErr, serverErr = usecase.GetTodo(userId, todoId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if todo == nil {
+		usecaseErr = ErrTodoNotFound
+		return
+	}
+	if !todo.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
+	statusFound, err := usecase.todoRepository.GetStatusTodo(userId, statusId)
+	if err != nil {
+		serverErr = err
+		return
+	}
+	if statusFound == nil {
+		usecaseErr = ErrStatusTodoNotFound
+		return
+	}
+
+	completedAt, usecaseErr, serverErr := usecase.transitionTodo(todo, statusFound)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	position, err := usecase.todoRepository.PositionTodo(userId, todo, statusId, previousId, nextId, completedAt)
+	if errors.Is(err, ErrTodoNeighborNotFound) || errors.Is(err, ErrTodoMoveConflict) || errors.Is(err, ErrStatusWipLimitReached) {
+		usecaseErr = err
+		return
+	}
+	if err != nil {
+		serverErr = err
+		return
+	}
+	todo.StatusID = statusId
+	todo.Position = position
+	todo.CompletedAt = completedAt
+	return
+}
+
+// transitionTodo checks the todo can go to the status and returns its
+// completedAt there, stamped when it enters a done status and cleared
+// when it leaves
+func (usecase *DBTodoUsecase) transitionTodo(todo *Todo, statusTo *StatusTodo) (completedAt *time.Time, usecaseErr error, serverErr error) {
+	if todo.BoardId != statusTo.BoardId {
+		usecaseErr = ErrStatusOtherBoard
+		return
+	}
+	if todo.StatusID != statusTo.ID {
+		toStatusIds, err := usecase.todoRepository.GetStatusTransitions(todo.StatusID)
+		if err != nil {
+			serverErr = err
+			return
+		}
+		// without rules the todo goes to any status
+		allowed := len(toStatusIds) == 0
+		for _, toStatusId := range toStatusIds {
+			if toStatusId == statusTo.ID {
+				allowed = true
+			}
+		}
+		if !allowed {
+			usecaseErr = ErrStatusTransitionDenied
+			return
+		}
+	}
+
+	if statusTo.Category != StatusCategoryDone {
+		return
+	}
+	completedAt = todo.CompletedAt
+	if completedAt == nil {
+		now := time.Now().UTC()
+		completedAt = &now
+	}
+	return
+}
+
+func (usecase *DBTodoUsecase) GetTodo(userId, todoID int64) (todo *Todo, usecaseErr error, serverErr error) {
 	if todoID <= 0 {
 		usecaseErr = ErrTodoIdIsNegative
 		return
 	}
-	todo, serverErr = usecase.todoRepository.GetTodo(todoID)
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	todo, serverErr = usecase.todoRepository.GetTodo(userId, todoID)
 	return
 }
 
-func (usecase *DBTodoUsecase) GetAllTodo() (todos []*Todo, usecaseErr error, serverErr error) {
-	todos, serverErr = usecase.todoRepository.GetAllTodo()
+func (usecase *DBTodoUsecase) GetAllTodo(query TodoQuery) (page *TodoPage, usecaseErr error, serverErr error) {
+	if query.UserId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	// a status column is listed in its order
+	if query.Sort == "" && query.StatusId > 0 {
+		query.Sort = TodoSortPosition
+		if query.Direction == "" {
+			query.Direction = SortAsc
+		}
+	}
+	if query.Sort == "" {
+		query.Sort = TodoSortCreatedAt
+	}
+	if query.Direction == "" {
+		query.Direction = SortDesc
+	}
+	if query.Limit == 0 {
+		query.Limit = TodoQueryDefaultLimit
+	}
+	if query.Now.IsZero() {
+		query.Now = time.Now()
+	}
+	usecaseErr = query.Valid()
+	if usecaseErr != nil {
+		return
+	}
+
+	// one more todo tells if there is a next page
+	queryNextPage := query
+	queryNextPage.Limit = query.Limit + 1
+	todos, serverErr := usecase.todoRepository.GetAllTodo(queryNextPage)
+	if serverErr != nil {
+		return
+	}
+	total, serverErr := usecase.todoRepository.CountTodo(query)
+	if serverErr != nil {
+		return
+	}
+
+	page = &TodoPage{Todos: todos, Total: total}
+	if len(todos) > query.Limit {
+		page.Todos = todos[:query.Limit]
+		page.NextCursor = NewTodoCursor(query, page.Todos[query.Limit-1])
+	}
 	return
 }
 
-func (usecase *DBTodoUsecase) UpdateImageTodo(dto *UpdateImageTodoDTO) (usecaseErr error, serverErr error) {
-	todoFound, usecaseErr, serverErr := usecase.GetTodo(dto.TodoId)
+func (usecase *DBTodoUsecase) UpdateImageTodo(userId int64, dto *UpdateImageTodoDTO) (usecaseErr error, serverErr error) {
+	todoFound, usecaseErr, serverErr := usecase.GetTodo(userId, dto.TodoId)
 	if usecaseErr != nil || serverErr != nil {
 		return
 	}
@@ -180,13 +495,17 @@ func (usecase *DBTodoUsecase) UpdateImageTodo(dto *UpdateImageTodoDTO) (usecaseE
 		usecaseErr = ErrTodoNotFound
 		return
 	}
+	if !todoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
 
-	serverErr = usecase.todoRepository.UpdateImageTodo(dto.TodoId, &dto.BufferFile)
+	serverErr = usecase.todoRepository.UpdateImageTodo(userId, todoFound, &dto.BufferFile)
 	return
 }
 
-func (usecase *DBTodoUsecase) GetImageTodo(todoId int64) (image *bytes.Buffer, usecaseErr error, serverErr error) {
-	todoFound, usecaseErr, serverErr := usecase.GetTodo(todoId)
+func (usecase *DBTodoUsecase) GetImageTodo(userId, todoId int64) (image *bytes.Buffer, usecaseErr error, serverErr error) {
+	todoFound, usecaseErr, serverErr := usecase.GetTodo(userId, todoId)
 	if usecaseErr != nil || serverErr != nil {
 		return
 	}
@@ -195,7 +514,7 @@ func (usecase *DBTodoUsecase) GetImageTodo(todoId int64) (image *bytes.Buffer, u
 		return
 	}
 
-	imageFound, serverErr := usecase.todoRepository.GetImageTodo(todoId)
+	imageFound, serverErr := usecase.todoRepository.GetImageTodo(userId, todoId)
 	if serverErr != nil {
 		return
 	}
@@ -207,8 +526,8 @@ func (usecase *DBTodoUsecase) GetImageTodo(todoId int64) (image *bytes.Buffer, u
 	return
 }
 
-func (usecase *DBTodoUsecase) DeleteImageTodo(todoID int64) (usecaseErr error, serverErr error) {
-	todoFound, usecaseErr, serverErr := usecase.GetTodo(todoID)
+func (usecase *DBTodoUsecase) DeleteImageTodo(userId, todoID int64) (usecaseErr error, serverErr error) {
+	todoFound, usecaseErr, serverErr := usecase.GetTodo(userId, todoID)
 	if usecaseErr != nil || serverErr != nil {
 		return
 	}
@@ -216,23 +535,36 @@ func (usecase *DBTodoUsecase) DeleteImageTodo(todoID int64) (usecaseErr error, s
 		usecaseErr = ErrTodoNotFound
 		return
 	}
+	if !todoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
 
-	serverErr = usecase.todoRepository.UpdateImageTodo(todoID, nil)
+	serverErr = usecase.todoRepository.UpdateImageTodo(userId, todoFound, nil)
 	return
 }
 
-func (usecase *DBTodoUsecase) CreateStatusTodo(name string, userId int64) (statusTodo *StatusTodo, usecaseErr error, serverErr error) {
+func (usecase *DBTodoUsecase) CreateStatusTodo(name string, userId, boardId int64, color string, wipLimit *int64, category StatusCategory) (statusTodo *StatusTodo, usecaseErr error, serverErr error) {
 	if userId <= 0 {
 		usecaseErr = ErrUserIdNegative
 		return
 	}
+	usecaseErr = validStatusTodoColumn(color, wipLimit, category)
+	if usecaseErr != nil {
+		return
+	}
 
 	if len(name) < 2 || len(name) > 255 {
 		usecaseErr = ErrNameStatusTodoIsSmall
 		return
 	}
 
-	statusTodoFound, err := usecase.todoRepository.GetStatusTodoByName(userId, name)
+	usecaseErr, serverErr = usecase.findBoard(userId, boardId, true)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+
+	statusTodoFound, err := usecase.todoRepository.GetStatusTodoByName(userId, boardId, name)
 	if err != nil {
 		serverErr = err
 		return
@@ -242,7 +574,7 @@ func (usecase *DBTodoUsecase) CreateStatusTodo(name string, userId int64) (statu
 		return
 	}
 
-	statusTodo, err = usecase.todoRepository.InsertStatusTodo(name, userId)
+	statusTodo, err = usecase.todoRepository.InsertStatusTodo(name, userId, boardId, color, wipLimit, category)
 	if err != nil {
 		serverErr = err
 		return
@@ -250,11 +582,18 @@ func (usecase *DBTodoUsecase) CreateStatusTodo(name string, userId int64) (statu
 	return
 }
 
-func (usecase *DBTodoUsecase) UpdateStatusTodo(userId, statusTodoId int64, name string) (usecaseErr error, serverErr error) {
+// UpdateStatusTodo doesn't move the todos out of a status over its new
+// wip limit, only the new ones are rejected. The completedAt of the todos
+// on it don't change with the category.
+func (usecase *DBTodoUsecase) UpdateStatusTodo(userId, statusTodoId int64, name, color string, wipLimit *int64, category StatusCategory) (usecaseErr error, serverErr error) {
 	if userId <= 0 {
 		usecaseErr = ErrUserIdNegative
 		return
 	}
+	usecaseErr = validStatusTodoColumn(color, wipLimit, category)
+	if usecaseErr != nil {
+		return
+	}
 
 	if len(name) < 2 || len(name) > 255 {
 		usecaseErr = ErrNameStatusTodoIsSmall
@@ -274,8 +613,12 @@ func (usecase *DBTodoUsecase) UpdateStatusTodo(userId, statusTodoId int64, name
 		usecaseErr = ErrStatusTodoNotFound
 		return
 	}
+	if !statusTodoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
 
-	statusTodoFoundByName, serverErr := usecase.todoRepository.GetStatusTodoByName(userId, name)
+	statusTodoFoundByName, serverErr := usecase.todoRepository.GetStatusTodoByName(userId, statusTodoFound.BoardId, name)
 	if serverErr != nil {
 		return
 	}
@@ -286,7 +629,118 @@ func (usecase *DBTodoUsecase) UpdateStatusTodo(userId, statusTodoId int64, name
 		}
 	}
 
-	serverErr = usecase.todoRepository.UpdateStatusTodo(statusTodoId, name, userId)
+	serverErr = usecase.todoRepository.UpdateStatusTodo(statusTodoId, name, userId, color, wipLimit, category)
+	return
+}
+
+func validStatusTodoColumn(color string, wipLimit *int64, category StatusCategory) error {
+	if wipLimit != nil && *wipLimit <= 0 {
+		return ErrStatusWipLimitInvalid
+	}
+	if err := category.Valid(); err != nil {
+		return err
+	}
+	return ValidColor(color)
+}
+
+func (usecase *DBTodoUsecase) GetStatusTransitions(userId, statusId int64) (toStatus []*StatusTodo, usecaseErr error, serverErr error) {
+	statusTodoFound, usecaseErr, serverErr := usecase.GetStatusTodo(userId, statusId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if statusTodoFound == nil {
+		usecaseErr = ErrStatusTodoNotFound
+		return
+	}
+	toStatusIds, serverErr := usecase.todoRepository.GetStatusTransitions(statusId)
+	if serverErr != nil {
+		return
+	}
+	toStatus = make([]*StatusTodo, 0, len(toStatusIds))
+	for _, toStatusId := range toStatusIds {
+		statusTo, err := usecase.todoRepository.GetStatusTodo(userId, toStatusId)
+		if err != nil {
+			serverErr = err
+			return
+		}
+		if statusTo != nil {
+			toStatus = append(toStatus, statusTo)
+		}
+	}
+	return
+}
+
+// SetStatusTransitions replaces the statuses allowed after the status,
+// an empty list allows every status again
+func (usecase *DBTodoUsecase) SetStatusTransitions(userId, statusId int64, toStatusIds []int64) (toStatus []*StatusTodo, usecaseErr error, serverErr error) {
+	statusTodoFound, usecaseErr, serverErr := usecase.GetStatusTodo(userId, statusId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if statusTodoFound == nil {
+		usecaseErr = ErrStatusTodoNotFound
+		return
+	}
+	if !statusTodoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
+	for _, toStatusId := range toStatusIds {
+		if toStatusId == statusId {
+			usecaseErr = ErrStatusTransitionInvalid
+			return
+		}
+		statusTo, err := usecase.todoRepository.GetStatusTodo(userId, toStatusId)
+		if err != nil {
+			serverErr = err
+			return
+		}
+		if statusTo == nil || statusTo.BoardId != statusTodoFound.BoardId {
+			usecaseErr = ErrStatusTransitionInvalid
+			return
+		}
+	}
+
+	serverErr = usecase.todoRepository.ReplaceStatusTransitions(statusId, toStatusIds)
+	if serverErr != nil {
+		return
+	}
+	return usecase.GetStatusTransitions(userId, statusId)
+}
+
+// ReorderStatusTodo needs every status of the board in the new order and
+// returns them in that order
+func (usecase *DBTodoUsecase) ReorderStatusTodo(userId, boardId int64, statusIds []int64) (allStatusTodo []*StatusTodo, usecaseErr error, serverErr error) {
+	usecaseErr, serverErr = usecase.findBoard(userId, boardId, true)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	allStatusTodo, usecaseErr, serverErr = usecase.GetAllStatusTodo(userId, boardId)
+	if usecaseErr != nil || serverErr != nil {
+		return
+	}
+	if len(allStatusTodo) != len(statusIds) {
+		usecaseErr = ErrStatusOrderInvalid
+		return
+	}
+	statusById := make(map[int64]bool, len(allStatusTodo))
+	for _, statusTodo := range allStatusTodo {
+		statusById[statusTodo.ID] = true
+	}
+	for _, statusId := range statusIds {
+		if !statusById[statusId] {
+			usecaseErr = ErrStatusOrderInvalid
+			return
+		}
+		// each id only once
+		delete(statusById, statusId)
+	}
+
+	serverErr = usecase.todoRepository.ReorderStatusTodo(userId, statusIds)
+	if serverErr != nil {
+		return
+	}
+	allStatusTodo, serverErr = usecase.todoRepository.GetAllStatusTodo(userId, boardId)
 	return
 }
 
@@ -309,12 +763,51 @@ func (usecase *DBTodoUsecase) GetStatusTodo(userId, statusId int64) (statusTodo
 	return
 }
 
-func (usecase *DBTodoUsecase) GetAllStatusTodo() (allStatusTodo []*StatusTodo, usecaseErr error, serverErr error) {
-	allStatusTodo, serverErr = usecase.todoRepository.GetAllStatusTodo()
+// GetAllStatusTodo returns the statuses of every board of the user when
+// boardId is 0
+func (usecase *DBTodoUsecase) GetAllStatusTodo(userId, boardId int64) (allStatusTodo []*StatusTodo, usecaseErr error, serverErr error) {
+	if userId <= 0 {
+		usecaseErr = ErrUserIdNegative
+		return
+	}
+	if boardId != 0 {
+		usecaseErr, serverErr = usecase.findBoard(userId, boardId, false)
+		if usecaseErr != nil || serverErr != nil {
+			return
+		}
+	}
+	allStatusTodo, serverErr = usecase.todoRepository.GetAllStatusTodo(userId, boardId)
 	return
 }
 
-func (usecase *DBTodoUsecase) DeleteStatusTodo(userId, statusId int64) (usecaseErr error, serverErr error) {
+// findBoard makes sure the user is a member of the board, with a role that
+// changes it when write
+func (usecase *DBTodoUsecase) findBoard(userId, boardId int64, write bool) (usecaseErr error, serverErr error) {
+	if boardId <= 0 {
+		usecaseErr = ErrBoardIdNegative
+		return
+	}
+	boardFound, serverErr := usecase.boardRepository.GetBoard(userId, boardId)
+	if serverErr != nil {
+		return
+	}
+	if boardFound == nil {
+		usecaseErr = ErrBoardNotFound
+		return
+	}
+	if write && !boardFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+	}
+	return
+}
+
+// DeleteStatusTodo with todos needs a mode: moveToStatusId moves them to
+// another status and cascade deletes them, affected counts them
+func (usecase *DBTodoUsecase) DeleteStatusTodo(userId, statusId, moveToStatusId int64, cascade bool) (affected int64, usecaseErr error, serverErr error) {
+	if moveToStatusId != 0 && cascade {
+		usecaseErr = ErrStatusDeleteModeInvalid
+		return
+	}
 	if statusId <= 0 {
 		usecaseErr = ErrStatusTodoIdNegative
 		return
@@ -333,9 +826,38 @@ func (usecase *DBTodoUsecase) DeleteStatusTodo(userId, statusId int64) (usecaseE
 		usecaseErr = ErrStatusTodoNotFound
 		return
 	}
+	if !statusTodoFound.Role.CanWrite() {
+		usecaseErr = ErrBoardReadOnly
+		return
+	}
+
+	if cascade {
+		affected, serverErr = usecase.todoRepository.DeleteStatusTodoCascade(userId, statusId)
+		return
+	}
+	if moveToStatusId != 0 {
+		if moveToStatusId == statusId {
+			usecaseErr = ErrMoveToStatusInvalid
+			return
+		}
+		statusTo, err := usecase.todoRepository.GetStatusTodo(userId, moveToStatusId)
+		if err != nil {
+			serverErr = err
+			return
+		}
+		if statusTo == nil || statusTo.BoardId != statusTodoFound.BoardId {
+			usecaseErr = ErrMoveToStatusInvalid
+			return
+		}
+		affected, serverErr = usecase.todoRepository.DeleteStatusTodoMovingTodos(userId, statusId, moveToStatusId)
+		if errors.Is(serverErr, ErrStatusWipLimitReached) {
+			usecaseErr, serverErr = serverErr, nil
+		}
+		return
+	}
 
 	countTodoOnStatusTodo, serverErr := usecase.todoRepository.CountTodoByStatus(statusId)
-	if usecaseErr != nil {
+	if serverErr != nil {
 		return
 	}
 	if countTodoOnStatusTodo > 0 {
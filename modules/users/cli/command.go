@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// exit codes of the admin commands
+const (
+	ExitSuccess      = 0
+	ExitServerError  = 1
+	ExitUsageError   = 2
+	ExitUsecaseError = 3
+)
+
+// Command runs with the arguments after its name and returns the exit code
+type Command func(args []string) int
+
+// Response is the json written on stdout by every command
+type Response struct {
+	Ok      bool        `json:"ok"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+var output io.Writer = os.Stdout
+
+func respond(data interface{}) int {
+	writeResponse(Response{Ok: true, Data: data})
+	return ExitSuccess
+}
+
+func respondUsecaseError(usecaseErr error) int {
+	writeResponse(Response{Message: usecaseErr.Error()})
+	return ExitUsecaseError
+}
+
+func respondServerError(serverErr error) int {
+	fmt.Fprintln(os.Stderr, serverErr)
+	writeResponse(Response{Message: "server error"})
+	return ExitServerError
+}
+
+func respondUsageError(message string) int {
+	writeResponse(Response{Message: message})
+	return ExitUsageError
+}
+
+func writeResponse(response Response) {
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(response)
+}
+
+// newFlagSet doesn't print anything, parse errors are returned as json
+func newFlagSet(name string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.SetOutput(ioutil.Discard)
+	return flagSet
+}
+
+// Router finds the command by its group and name, like "user create"
+type Router struct {
+	commands map[string]map[string]Command
+}
+
+func NewRouter() *Router {
+	return &Router{map[string]map[string]Command{}}
+}
+
+func (router *Router) Handle(group, name string, command Command) {
+	if router.commands[group] == nil {
+		router.commands[group] = map[string]Command{}
+	}
+	router.commands[group][name] = command
+}
+
+func (router *Router) Run(args []string) int {
+	if len(args) < 2 {
+		return respondUsageError("usage: <group> <command> [flags], commands: " + router.usage())
+	}
+	command, found := router.commands[args[0]][args[1]]
+	if !found {
+		return respondUsageError(fmt.Sprintf("unknown command %q, commands: %s", strings.Join(args[:2], " "), router.usage()))
+	}
+	return command(args[2:])
+}
+
+func (router *Router) usage() string {
+	commands := make([]string, 0)
+	for group, groupCommands := range router.commands {
+		for name := range groupCommands {
+			commands = append(commands, group+" "+name)
+		}
+	}
+	sort.Strings(commands)
+	return strings.Join(commands, ", ")
+}
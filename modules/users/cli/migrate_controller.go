@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"api/database"
+)
+
+type MigrateController interface {
+	MigrateUp() Command
+	MigrateDown() Command
+}
+
+type MigrateControllerCli struct {
+	migrator *database.Migrator
+}
+
+func NewMigrateController(migrator *database.Migrator) MigrateController {
+	return &MigrateControllerCli{migrator}
+}
+
+func (controller *MigrateControllerCli) MigrateUp() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("migrate up")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+
+		applied, serverErr := controller.migrator.Up()
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		return respond(map[string]interface{}{"applied": applied})
+	}
+}
+
+func (controller *MigrateControllerCli) MigrateDown() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("migrate down")
+		steps := flagSet.Int("steps", 1, "how many migrations to revert")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+		if *steps <= 0 {
+			return respondUsageError("flag -steps should be positive")
+		}
+
+		reverted, serverErr := controller.migrator.Down(*steps)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		return respond(map[string]interface{}{"reverted": reverted})
+	}
+}
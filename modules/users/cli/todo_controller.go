@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"api/modules/todos"
+)
+
+type TodoController interface {
+	GetAllStatusTodo() Command
+	ExportTodos() Command
+}
+
+type TodoControllerCli struct {
+	todoUsecase todos.TodoUsecase
+}
+
+func NewTodoController(todoUsecase todos.TodoUsecase) TodoController {
+	return &TodoControllerCli{todoUsecase}
+}
+
+func (controller *TodoControllerCli) GetAllStatusTodo() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("status list")
+		userId := flagSet.Int64("user", 0, "id of the user")
+		boardId := flagSet.Int64("board", 0, "id of the board, every board when 0")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+		if *userId <= 0 {
+			return respondUsageError("missing flag -user")
+		}
+
+		allStatusTodo, usecaseErr, serverErr := controller.todoUsecase.GetAllStatusTodo(*userId, *boardId)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		if usecaseErr != nil {
+			return respondUsecaseError(usecaseErr)
+		}
+		return respond(allStatusTodo)
+	}
+}
+
+// ExportTodos writes the status and the todos of the user, images are
+// referenced by url like on the http api
+func (controller *TodoControllerCli) ExportTodos() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("todo export")
+		userId := flagSet.Int64("user", 0, "id of the user")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+		if *userId <= 0 {
+			return respondUsageError("missing flag -user")
+		}
+
+		allStatusTodo, usecaseErr, serverErr := controller.todoUsecase.GetAllStatusTodo(*userId, 0)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		if usecaseErr != nil {
+			return respondUsecaseError(usecaseErr)
+		}
+
+		// walk every page of the todos
+		todosResponse := make([]*todos.TodoDtoHttpResponse, 0)
+		query := todos.TodoQuery{
+			UserId:    *userId,
+			Sort:      todos.TodoSortCreatedAt,
+			Direction: todos.SortAsc,
+			Limit:     todos.TodoQueryMaxLimit,
+		}
+		for {
+			page, usecaseErr, serverErr := controller.todoUsecase.GetAllTodo(query)
+			if serverErr != nil {
+				return respondServerError(serverErr)
+			}
+			if usecaseErr != nil {
+				return respondUsecaseError(usecaseErr)
+			}
+			for _, todo := range page.Todos {
+				todosResponse = append(todosResponse, todo.ToDtoHttpResponse())
+			}
+			if page.NextCursor == nil {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+		return respond(map[string]interface{}{
+			"userId": *userId,
+			"status": allStatusTodo,
+			"todos":  todosResponse,
+		})
+	}
+}
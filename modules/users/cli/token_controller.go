@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"api/modules/users/usecases"
+	"time"
+)
+
+type TokenController interface {
+	RevokeUserTokens() Command
+}
+
+type TokenControllerCli struct {
+	tokenRevocation usecases.TokenRevocation
+}
+
+func NewTokenController(tokenRevocation usecases.TokenRevocation) TokenController {
+	return &TokenControllerCli{tokenRevocation}
+}
+
+// RevokeUserTokens revokes the access and refresh tokens issued to the user
+// until -before, now by default
+func (controller *TokenControllerCli) RevokeUserTokens() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("token revoke")
+		userId := flagSet.Int64("user", 0, "id of the user")
+		beforeStr := flagSet.String("before", "", "RFC3339 time, tokens issued until it are revoked")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+		if *userId <= 0 {
+			return respondUsageError("missing flag -user")
+		}
+
+		before := time.Now()
+		if *beforeStr != "" {
+			var err error
+			before, err = time.Parse(time.RFC3339, *beforeStr)
+			if err != nil {
+				return respondUsageError("flag -before should be a RFC3339 time")
+			}
+			if before.After(time.Now()) {
+				return respondUsecaseError(usecases.ErrRevokeBeforeInFuture)
+			}
+		}
+
+		serverErr := controller.tokenRevocation.RevokeAllUserTokens(*userId, before)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		return respond(map[string]interface{}{"userId": *userId, "revokedBefore": before})
+	}
+}
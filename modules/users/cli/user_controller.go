@@ -1,31 +1,249 @@
 package cli
 
 import (
+	"api/modules/users/models"
 	"api/modules/users/usecases"
 	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
 )
 
 type UserController interface {
-	AddUserGenesis() error
+	AddUserGenesis(config GenesisUserConfig) error
+
+	CreateUser() Command
+	GetAllUser() Command
+	DisableUser() Command
+	SetRoleUser() Command
+	ResetPasswordUser() Command
+}
+
+// GenesisUserConfig is the bootstrap admin, the password can come from a
+// secret file and when both are empty a random password is generated
+type GenesisUserConfig struct {
+	Name         string
+	Username     string
+	Email        string
+	Password     string
+	PasswordFile string
 }
 
 type UserControllerCli struct {
-	userUsecase usecases.UserUsecase
+	userUsecase     usecases.UserUsecase
+	roleUsecase     usecases.RoleUsecase
+	tokenRevocation usecases.TokenRevocation
 }
 
-func NewUserController(userUsecase usecases.UserUsecase) UserController {
-	return &UserControllerCli{userUsecase}
+func NewUserController(
+	userUsecase usecases.UserUsecase,
+	roleUsecase usecases.RoleUsecase,
+	tokenRevocation usecases.TokenRevocation,
+) UserController {
+	return &UserControllerCli{userUsecase, roleUsecase, tokenRevocation}
 }
 
-func (controller *UserControllerCli) AddUserGenesis() error {
-	userCreated, usecaseErr, serverErr := controller.userUsecase.CreateGenesisUser()
+func (controller *UserControllerCli) AddUserGenesis(config GenesisUserConfig) error {
+	password := config.Password
+	if config.PasswordFile != "" {
+		content, err := ioutil.ReadFile(config.PasswordFile)
+		if err != nil {
+			return err
+		}
+		password = strings.TrimSpace(string(content))
+	}
+
+	userCreated, passwordGenerated, usecaseErr, serverErr := controller.userUsecase.CreateGenesisUser(
+		config.Name,
+		config.Username,
+		config.Email,
+		password,
+	)
 	if serverErr != nil {
 		return serverErr
 	}
-	if usecaseErr != nil {
-		fmt.Println(usecaseErr)
+	if usecaseErr == usecases.ErrAllreadyHaveUsers {
 		return nil
 	}
-	fmt.Println(userCreated)
+	if usecaseErr != nil {
+		return usecaseErr
+	}
+
+	fmt.Printf("[ * ] genesis user %q created, the password must be changed on first login\n", userCreated.Username)
+	if passwordGenerated != "" {
+		// printed only once, it is never stored in plain text
+		fmt.Printf("[ * ] genesis user password: %s\n", passwordGenerated)
+	}
 	return nil
 }
+
+func (controller *UserControllerCli) CreateUser() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("user create")
+		name := flagSet.String("name", "", "name of the user")
+		username := flagSet.String("username", "", "username used to login")
+		email := flagSet.String("email", "", "email of the user")
+		password := flagSet.String("password", "", "password of the user")
+		role := flagSet.String("role", "", "role of the user, basic by default")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+		if *password == "" {
+			return respondUsageError("missing flag -password")
+		}
+
+		userCreated, usecaseErr, serverErr := controller.userUsecase.CreateUser(*name, *username, *password, *password, *email)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		if usecaseErr != nil {
+			return respondUsecaseError(usecaseErr)
+		}
+
+		if *role != "" {
+			usecaseErr, serverErr = controller.roleUsecase.SetUserRole(userCreated.ID, *role)
+			if serverErr != nil {
+				return respondServerError(serverErr)
+			}
+			if usecaseErr != nil {
+				return respondUsecaseError(usecaseErr)
+			}
+			userCreated, usecaseErr, serverErr = controller.userUsecase.GetUser(userCreated.ID)
+			if serverErr != nil {
+				return respondServerError(serverErr)
+			}
+			if usecaseErr != nil {
+				return respondUsecaseError(usecaseErr)
+			}
+		}
+
+		return respond(userCreated.ToSafeHttp())
+	}
+}
+
+func (controller *UserControllerCli) GetAllUser() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("user list")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+
+		users, usecaseErr, serverErr := controller.userUsecase.GetAllUser()
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		if usecaseErr != nil {
+			return respondUsecaseError(usecaseErr)
+		}
+
+		usersSafe := make([]*models.UserSafeHttp, 0, len(users))
+		for _, user := range users {
+			usersSafe = append(usersSafe, user.ToSafeHttp())
+		}
+		return respond(usersSafe)
+	}
+}
+
+// DisableUser blocks the login and revokes every token of the user
+func (controller *UserControllerCli) DisableUser() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("user disable")
+		id := flagSet.Int64("id", 0, "id of the user")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+		if *id <= 0 {
+			return respondUsageError("missing flag -id")
+		}
+
+		usecaseErr, serverErr := controller.userUsecase.DisableUser(*id)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		if usecaseErr != nil {
+			return respondUsecaseError(usecaseErr)
+		}
+
+		serverErr = controller.tokenRevocation.RevokeAllUserTokens(*id, time.Now())
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		return respond(map[string]interface{}{"id": *id, "disabled": true})
+	}
+}
+
+func (controller *UserControllerCli) SetRoleUser() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("user set-role")
+		id := flagSet.Int64("id", 0, "id of the user")
+		role := flagSet.String("role", "", "name of the role")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+		if *id <= 0 {
+			return respondUsageError("missing flag -id")
+		}
+		if *role == "" {
+			return respondUsageError("missing flag -role")
+		}
+
+		usecaseErr, serverErr := controller.roleUsecase.SetUserRole(*id, *role)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		if usecaseErr != nil {
+			return respondUsecaseError(usecaseErr)
+		}
+
+		roles, usecaseErr, serverErr := controller.roleUsecase.GetUserRoles(*id)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		if usecaseErr != nil {
+			return respondUsecaseError(usecaseErr)
+		}
+		userFound, usecaseErr, serverErr := controller.userUsecase.GetUser(*id)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		if usecaseErr != nil {
+			return respondUsecaseError(usecaseErr)
+		}
+		return respond(map[string]interface{}{"user": userFound.ToSafeHttp(), "roles": roles})
+	}
+}
+
+// ResetPasswordUser without -password generates a random one, the user must
+// change it on the next login and the sessions are revoked
+func (controller *UserControllerCli) ResetPasswordUser() Command {
+	return func(args []string) int {
+		flagSet := newFlagSet("user reset-password")
+		id := flagSet.Int64("id", 0, "id of the user")
+		password := flagSet.String("password", "", "new password, random when empty")
+		if err := flagSet.Parse(args); err != nil {
+			return respondUsageError(err.Error())
+		}
+		if *id <= 0 {
+			return respondUsageError("missing flag -id")
+		}
+
+		passwordGenerated, usecaseErr, serverErr := controller.userUsecase.ResetPassword(*id, *password)
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+		if usecaseErr != nil {
+			return respondUsecaseError(usecaseErr)
+		}
+
+		serverErr = controller.tokenRevocation.RevokeAllUserTokens(*id, time.Now())
+		if serverErr != nil {
+			return respondServerError(serverErr)
+		}
+
+		data := map[string]interface{}{"id": *id, "mustChangePassword": true}
+		if passwordGenerated != "" {
+			data["password"] = passwordGenerated
+		}
+		return respond(data)
+	}
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"api/modules/users/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KeySetController interface {
+	GetJWKS() func(c *gin.Context)
+}
+
+type KeySetControllerGin struct {
+	keySetProvider models.KeySetProvider
+}
+
+func NewKeySetController(keySetProvider models.KeySetProvider) KeySetController {
+	return &KeySetControllerGin{keySetProvider}
+}
+
+func (controller *KeySetControllerGin) GetJWKS() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, controller.keySetProvider.KeySet())
+	}
+}
@@ -2,15 +2,20 @@ package controllers
 
 import (
 	"api/modules/users/dto"
+	"api/modules/users/middlewares"
 	"api/modules/users/usecases"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type LoginController interface {
 	Login() func(c *gin.Context)
+	RefreshToken() func(c *gin.Context)
 	Logout() func(c *gin.Context)
+	LogoutAll() func(c *gin.Context)
 }
 
 type LoginControllerGin struct {
@@ -50,6 +55,107 @@ func (controller *LoginControllerGin) Login() func(c *gin.Context) {
 	}
 }
 
+func (controller *LoginControllerGin) RefreshToken() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		var body dto.RefreshTokenBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err := body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		loginResponse, usecaseErr, serverErr := controller.loginUsecase.Refresh(body.RefreshToken)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		loginResponseSafe := loginResponse.ToSafeHttp()
+		c.JSON(http.StatusCreated, loginResponseSafe)
+	}
+}
+
 func (controller *LoginControllerGin) Logout() func(c *gin.Context) {
-	return func(c *gin.Context) {}
+	return func(c *gin.Context) {
+		// body is optional, with the refresh token its family is revoked too
+		var body dto.LogoutBody
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&body); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "invalid body"})
+				return
+			}
+		}
+		body.ProcessData()
+
+		// get token
+		tokenValue, exists := c.Get(middlewares.Token)
+		if !exists {
+			fmt.Println("need token for logout")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		token := tokenValue.(string)
+
+		_, usecaseErr, serverErr := controller.loginUsecase.Logout(token, body.RefreshToken)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *LoginControllerGin) LogoutAll() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		// body is optional, without it every token until now is revoked
+		var body dto.LogoutAllBody
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&body); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "invalid body"})
+				return
+			}
+		}
+		before := time.Now()
+		if body.Before != nil {
+			before = *body.Before
+		}
+
+		// get user id
+		userIdValue, exists := c.Get(middlewares.UserId)
+		if !exists {
+			fmt.Println("need user id for logout all")
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		userId := userIdValue.(int64)
+
+		usecaseErr, serverErr := controller.loginUsecase.LogoutAll(userId, before)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
 }
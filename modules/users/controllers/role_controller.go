@@ -0,0 +1,286 @@
+package controllers
+
+import (
+	"api/modules/users/dto"
+	"api/modules/users/usecases"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleController interface {
+	CreateRole() func(c *gin.Context)
+	UpdateRole() func(c *gin.Context)
+	DeleteRole() func(c *gin.Context)
+	GetRole() func(c *gin.Context)
+	GetAllRole() func(c *gin.Context)
+
+	GetUserRoles() func(c *gin.Context)
+	AssignRoleToUser() func(c *gin.Context)
+	UnassignRoleFromUser() func(c *gin.Context)
+}
+
+type RoleControllerGin struct {
+	roleUsecase usecases.RoleUsecase
+}
+
+func NewRoleController(roleUsecase usecases.RoleUsecase) RoleController {
+	return &RoleControllerGin{roleUsecase}
+}
+
+func roleUsecaseErrStatusCode(usecaseErr error) int {
+	switch usecaseErr {
+	case usecases.ErrRoleNotFound, usecases.ErrUserNotFound:
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+func (controller *RoleControllerGin) CreateRole() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		var body dto.RoleBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err := body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		roleCreated, usecaseErr, serverErr := controller.roleUsecase.CreateRole(body.Name, body.Permissions)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(roleUsecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, roleCreated)
+	}
+}
+
+func (controller *RoleControllerGin) UpdateRole() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing role id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing role id integer on url param"})
+			return
+		}
+
+		var body dto.RoleBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		body.ProcessData()
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		usecaseErr, serverErr := controller.roleUsecase.UpdateRole(id, body.Name, body.Permissions)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(roleUsecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *RoleControllerGin) DeleteRole() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing role id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing role id integer on url param"})
+			return
+		}
+
+		usecaseErr, serverErr := controller.roleUsecase.DeleteRole(id)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(roleUsecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *RoleControllerGin) GetRole() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing role id on url param"})
+			return
+		}
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing role id integer on url param"})
+			return
+		}
+
+		roleFound, usecaseErr, serverErr := controller.roleUsecase.GetRole(id)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(roleUsecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, roleFound)
+	}
+}
+
+func (controller *RoleControllerGin) GetAllRole() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		roles, usecaseErr, serverErr := controller.roleUsecase.GetAllRole()
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(roleUsecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, roles)
+	}
+}
+
+func (controller *RoleControllerGin) GetUserRoles() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id on url param"})
+			return
+		}
+		userId, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id integer on url param"})
+			return
+		}
+
+		roles, usecaseErr, serverErr := controller.roleUsecase.GetUserRoles(userId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(roleUsecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, roles)
+	}
+}
+
+func (controller *RoleControllerGin) AssignRoleToUser() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id on url param"})
+			return
+		}
+		userId, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id integer on url param"})
+			return
+		}
+
+		var body dto.AssignRoleBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing body"})
+			return
+		}
+		err = body.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		usecaseErr, serverErr := controller.roleUsecase.AssignRoleToUser(userId, body.RoleId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(roleUsecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (controller *RoleControllerGin) UnassignRoleFromUser() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		idStr, hasId := c.Params.Get("id")
+		if !hasId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id on url param"})
+			return
+		}
+		userId, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing user id integer on url param"})
+			return
+		}
+		roleIdStr, hasRoleId := c.Params.Get("roleId")
+		if !hasRoleId {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing role id on url param"})
+			return
+		}
+		roleId, err := strconv.ParseInt(roleIdStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "missing role id integer on url param"})
+			return
+		}
+
+		usecaseErr, serverErr := controller.roleUsecase.UnassignRoleFromUser(userId, roleId)
+		if serverErr != nil {
+			fmt.Println(serverErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(roleUsecaseErrStatusCode(usecaseErr), gin.H{"message": usecaseErr.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"api/modules/users/dto"
+	"api/modules/users/middlewares"
 	"api/modules/users/models"
 	"api/modules/users/usecases"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -25,10 +27,31 @@ type UserController interface {
 
 type UserControllerGin struct {
 	userUsecase usecases.UserUsecase
+	userPolicy  usecases.UserPolicy
 }
 
-func NewUserController(userUsecase usecases.UserUsecase) UserController {
-	return &UserControllerGin{userUsecase}
+func NewUserController(userUsecase usecases.UserUsecase, userPolicy usecases.UserPolicy) UserController {
+	return &UserControllerGin{userUsecase, userPolicy}
+}
+
+// getActor reads the authenticated user set by the authorization middleware
+func getActor(c *gin.Context) (usecases.Actor, bool) {
+	userIdValue, hasUserId := c.Get(middlewares.UserId)
+	levelAccessValue, hasLevelAccess := c.Get(middlewares.LevelAccess)
+	permissionsValue, hasPermissions := c.Get(middlewares.Permissions)
+	if !hasUserId || !hasLevelAccess || !hasPermissions {
+		fmt.Println("need user id, level access and permissions from authorization middleware")
+		return usecases.Actor{}, false
+	}
+	return usecases.Actor{
+		UserId:      userIdValue.(int64),
+		LevelAccess: levelAccessValue.(models.LevelAccess),
+		Permissions: permissionsValue.(models.Permissions),
+	}, true
+}
+
+func abortWithPolicyErr(c *gin.Context, policyErr error) {
+	c.JSON(http.StatusForbidden, gin.H{"message": policyErr.Error()})
 }
 
 func (controller *UserControllerGin) CreateUser() func(c *gin.Context) {
@@ -85,7 +108,34 @@ func (controller *UserControllerGin) UpdateUser() func(c *gin.Context) {
 			return
 		}
 
-		usecaseErr, serverErr := controller.userUsecase.UpdateUser(id, body.Name, body.Username, body.Password, body.Email, body.LevelAccess)
+		// check authorization
+		actor, ok := getActor(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if policyErr := controller.userPolicy.CanUpdateUser(actor, id); policyErr != nil {
+			abortWithPolicyErr(c, policyErr)
+			return
+		}
+		userFound, usecaseErr, serverErr := controller.userUsecase.GetUser(id)
+		if serverErr != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if usecaseErr != nil {
+			c.JSON(http.StatusNotFound, gin.H{"message": usecaseErr.Error()})
+			return
+		}
+		if body.LevelAccess == 0 {
+			body.LevelAccess = userFound.LevelAccess
+		}
+		if policyErr := controller.userPolicy.CanChangeLevelAccess(actor, userFound, body.LevelAccess); policyErr != nil {
+			abortWithPolicyErr(c, policyErr)
+			return
+		}
+
+		usecaseErr, serverErr = controller.userUsecase.UpdateUser(id, body.Name, body.Username, body.Password, body.Email, body.LevelAccess)
 		if serverErr != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
 			return
@@ -112,6 +162,17 @@ func (controller *UserControllerGin) DeleteUser() func(c *gin.Context) {
 			return
 		}
 
+		// check authorization
+		actor, ok := getActor(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if policyErr := controller.userPolicy.CanDeleteUser(actor, id); policyErr != nil {
+			abortWithPolicyErr(c, policyErr)
+			return
+		}
+
 		usecaseErr, serverErr := controller.userUsecase.DeleteUser(id)
 		if serverErr != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
@@ -139,6 +200,17 @@ func (controller *UserControllerGin) GetUser() func(c *gin.Context) {
 			return
 		}
 
+		// check authorization
+		actor, ok := getActor(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if policyErr := controller.userPolicy.CanGetUser(actor, id); policyErr != nil {
+			abortWithPolicyErr(c, policyErr)
+			return
+		}
+
 		userFound, usecaseErr, serverErr := controller.userUsecase.GetUser(id)
 		if serverErr != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
@@ -149,7 +221,7 @@ func (controller *UserControllerGin) GetUser() func(c *gin.Context) {
 			return
 		}
 		if userFound == nil {
-			c.JSON(http.StatusNotFound, gin.H{"message": "todo not found"})
+			c.JSON(http.StatusNotFound, gin.H{"message": "user not found"})
 			return
 		}
 
@@ -160,6 +232,17 @@ func (controller *UserControllerGin) GetUser() func(c *gin.Context) {
 
 func (controller *UserControllerGin) GetAllUser() func(c *gin.Context) {
 	return func(c *gin.Context) {
+		// check authorization
+		actor, ok := getActor(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if policyErr := controller.userPolicy.CanGetAllUser(actor); policyErr != nil {
+			abortWithPolicyErr(c, policyErr)
+			return
+		}
+
 		users, usecaseErr, serverErr := controller.userUsecase.GetAllUser()
 		if serverErr != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
@@ -204,6 +287,17 @@ func (controller *UserControllerGin) ChangePassword() func(c *gin.Context) {
 			return
 		}
 
+		// check authorization
+		actor, ok := getActor(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if policyErr := controller.userPolicy.CanChangePassword(actor, id); policyErr != nil {
+			abortWithPolicyErr(c, policyErr)
+			return
+		}
+
 		usecaseErr, serverErr := controller.userUsecase.ChangePassword(id, body.OldPassword, body.NewPassword, body.NewPasswordConfirmation)
 		if serverErr != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
@@ -232,6 +326,17 @@ func (controller *UserControllerGin) UpdatePhotoUser() func(c *gin.Context) {
 			return
 		}
 
+		// check authorization
+		actor, ok := getActor(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if policyErr := controller.userPolicy.CanUpdatePhotoUser(actor, id); policyErr != nil {
+			abortWithPolicyErr(c, policyErr)
+			return
+		}
+
 		// get photo
 		fileHeader, _ := c.FormFile("photo")
 		userPhotoDto, err := dto.NewUpdatePhotoUserDTO(id, fileHeader)
@@ -273,6 +378,17 @@ func (controller *UserControllerGin) DeletePhotoUser() func(c *gin.Context) {
 			return
 		}
 
+		// check authorization
+		actor, ok := getActor(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if policyErr := controller.userPolicy.CanUpdatePhotoUser(actor, id); policyErr != nil {
+			abortWithPolicyErr(c, policyErr)
+			return
+		}
+
 		usecaseErr, serverErr := controller.userUsecase.DeletePhotoUser(id)
 		if serverErr != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "server error"})
@@ -4,6 +4,7 @@ import (
 	"api/modules/users/models"
 	"errors"
 	"strings"
+	"time"
 )
 
 type LoginBody struct {
@@ -27,18 +28,48 @@ func (body *LoginBody) ProcessData() {
 }
 
 type LoginResponse struct {
-	Token string      `json:"token"`
-	User  models.User `json:"user"`
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refreshToken"`
+	User         models.User `json:"user"`
 }
 
 type LoginResponseSafe struct {
-	Token string              `json:"token"`
-	User  models.UserSafeHttp `json:"user"`
+	Token        string              `json:"token"`
+	RefreshToken string              `json:"refreshToken"`
+	User         models.UserSafeHttp `json:"user"`
 }
 
 func (dto *LoginResponse) ToSafeHttp() *LoginResponseSafe {
 	return &LoginResponseSafe{
 		dto.Token,
+		dto.RefreshToken,
 		*dto.User.ToSafeHttp(),
 	}
 }
+
+type RefreshTokenBody struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+func (body *RefreshTokenBody) Validate() error {
+	if body.RefreshToken == "" {
+		return errors.New("refresh token is empty")
+	}
+	return nil
+}
+
+func (body *RefreshTokenBody) ProcessData() {
+	body.RefreshToken = strings.TrimSpace(body.RefreshToken)
+}
+
+type LogoutBody struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+func (body *LogoutBody) ProcessData() {
+	body.RefreshToken = strings.TrimSpace(body.RefreshToken)
+}
+
+type LogoutAllBody struct {
+	Before *time.Time `json:"before"`
+}
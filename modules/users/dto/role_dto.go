@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"api/modules/users/models"
+	"errors"
+	"strings"
+)
+
+type RoleBody struct {
+	Name        string             `json:"name"`
+	Permissions models.Permissions `json:"permissions"`
+}
+
+func (body *RoleBody) Validate() error {
+	if body.Name == "" {
+		return errors.New("name is empty")
+	}
+	return nil
+}
+
+func (body *RoleBody) ProcessData() {
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Permissions == nil {
+		body.Permissions = make(models.Permissions, 0)
+	}
+}
+
+type AssignRoleBody struct {
+	RoleId int64 `json:"roleId"`
+}
+
+func (body *AssignRoleBody) Validate() error {
+	if body.RoleId <= 0 {
+		return errors.New("role id should be positive")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"api/modules/users/models"
+)
+
+type RefreshTokenRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) models.RefreshTokenRepository {
+	return &RefreshTokenRepositoryPG{db}
+}
+
+func (repo *RefreshTokenRepositoryPG) InsertRefreshToken(userId int64, familyId, tokenHash string, expiredAt time.Time) (*models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	sqlInsert := `
+		INSERT INTO users.refresh_token (user_id, family_id, token_hash, expired_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at;
+	`
+	args := []interface{}{userId, familyId, tokenHash, expiredAt.UTC()}
+	row := repo.db.QueryRow(sqlInsert, args...)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err := row.Scan(&refreshToken.ID, &refreshToken.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken.UserId = userId
+	refreshToken.FamilyId = familyId
+	refreshToken.TokenHash = tokenHash
+	refreshToken.ExpiredAt = expiredAt
+	return &refreshToken, nil
+}
+
+func (repo *RefreshTokenRepositoryPG) GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	sqlGet := `
+		SELECT id, user_id, family_id, token_hash, expired_at, used_at, revoked_at, created_at
+		FROM users.refresh_token
+		WHERE token_hash=$1;
+	`
+	row := repo.db.QueryRow(sqlGet, tokenHash)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err := row.Scan(
+		&refreshToken.ID,
+		&refreshToken.UserId,
+		&refreshToken.FamilyId,
+		&refreshToken.TokenHash,
+		&refreshToken.ExpiredAt,
+		&refreshToken.UsedAt,
+		&refreshToken.RevokedAt,
+		&refreshToken.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &refreshToken, nil
+}
+
+// MarkRefreshTokenUsed answers false when another request already used the token
+func (repo *RefreshTokenRepositoryPG) MarkRefreshTokenUsed(id int64) (bool, error) {
+	now := time.Now().UTC()
+	sqlUpdate := `
+		UPDATE users.refresh_token
+		SET used_at=$2
+		WHERE
+			id=$1 AND
+			used_at IS NULL;
+	`
+	result, err := repo.db.Exec(sqlUpdate, id, now)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+func (repo *RefreshTokenRepositoryPG) RevokeRefreshTokenFamily(familyId string) error {
+	now := time.Now().UTC()
+	sqlUpdate := `
+		UPDATE users.refresh_token
+		SET revoked_at=$2
+		WHERE
+			family_id=$1 AND
+			revoked_at IS NULL;
+	`
+	_, err := repo.db.Exec(sqlUpdate, familyId, now)
+	return err
+}
+
+func (repo *RefreshTokenRepositoryPG) RevokeUserRefreshTokens(userId int64) error {
+	now := time.Now().UTC()
+	sqlUpdate := `
+		UPDATE users.refresh_token
+		SET revoked_at=$2
+		WHERE
+			user_id=$1 AND
+			revoked_at IS NULL;
+	`
+	_, err := repo.db.Exec(sqlUpdate, userId, now)
+	return err
+}
+
+func (repo *RefreshTokenRepositoryPG) DeleteExpiredRefreshTokens(now time.Time) (int64, error) {
+	sqlDelete := `
+		DELETE FROM users.refresh_token
+		WHERE expired_at < $1;
+	`
+	result, err := repo.db.Exec(sqlDelete, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
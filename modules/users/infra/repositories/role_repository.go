@@ -0,0 +1,308 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"api/modules/users/models"
+)
+
+type RoleRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) models.RoleRepository {
+	return &RoleRepositoryPG{db}
+}
+
+func (repo *RoleRepositoryPG) InsertRole(name string, permissions models.Permissions) (*models.Role, error) {
+	var role models.Role
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sqlInsert := `
+		INSERT INTO users.role (name)
+		VALUES ($1)
+		RETURNING id, created_at, updated_at;
+	`
+	row := tx.QueryRow(sqlInsert, name)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err = row.Scan(&role.ID, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertRolePermissions(tx, role.ID, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	role.Name = name
+	role.Permissions = permissions
+	return &role, nil
+}
+
+func (repo *RoleRepositoryPG) UpdateRole(id int64, name string, permissions models.Permissions) error {
+	now := time.Now().UTC()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sqlUpdate := `
+		UPDATE users.role
+		SET
+			name=$2,
+			updated_at=$3
+		WHERE id=$1;
+	`
+	_, err = tx.Exec(sqlUpdate, id, name, now)
+	if err != nil {
+		return err
+	}
+
+	sqlDelete := `
+		DELETE FROM users.role_permission
+		WHERE role_id=$1;
+	`
+	_, err = tx.Exec(sqlDelete, id)
+	if err != nil {
+		return err
+	}
+
+	err = insertRolePermissions(tx, id, permissions)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertRolePermissions(tx *sql.Tx, roleId int64, permissions models.Permissions) error {
+	sqlInsert := `
+		INSERT INTO users.role_permission (role_id, permission)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`
+	for _, permission := range permissions {
+		_, err := tx.Exec(sqlInsert, roleId, permission)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *RoleRepositoryPG) DeleteRole(id int64) error {
+	sqlDelete := `
+		DELETE FROM users.role
+		WHERE id=$1;
+	`
+	_, err := repo.db.Exec(sqlDelete, id)
+	return err
+}
+
+func (repo *RoleRepositoryPG) GetRole(id int64) (*models.Role, error) {
+	sqlGet := `
+		SELECT id, name, level_access, created_at, updated_at
+		FROM users.role
+		WHERE id=$1;
+	`
+	return repo.getRole(sqlGet, id)
+}
+
+func (repo *RoleRepositoryPG) GetRoleByName(name string) (*models.Role, error) {
+	sqlGet := `
+		SELECT id, name, level_access, created_at, updated_at
+		FROM users.role
+		WHERE LOWER(name)=$1;
+	`
+	return repo.getRole(sqlGet, strings.ToLower(name))
+}
+
+func (repo *RoleRepositoryPG) getRole(sqlGet string, args ...interface{}) (*models.Role, error) {
+	row := repo.db.QueryRow(sqlGet, args...)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	role, err := scanRole(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	err = repo.loadPermissions([]*models.Role{role})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (repo *RoleRepositoryPG) GetAllRole() ([]*models.Role, error) {
+	sqlGet := `
+		SELECT id, name, level_access, created_at, updated_at
+		FROM users.role
+		ORDER BY id;
+	`
+	return repo.getRoles(sqlGet)
+}
+
+func (repo *RoleRepositoryPG) GetUserRoles(userId int64) ([]*models.Role, error) {
+	sqlGet := `
+		SELECT r.id, r.name, r.level_access, r.created_at, r.updated_at
+		FROM users.role r
+		INNER JOIN users.user_role ur ON ur.role_id = r.id
+		WHERE ur.user_id=$1
+		ORDER BY r.id;
+	`
+	return repo.getRoles(sqlGet, userId)
+}
+
+func (repo *RoleRepositoryPG) getRoles(sqlGet string, args ...interface{}) ([]*models.Role, error) {
+	var roles = make([]*models.Role, 0)
+	rows, err := repo.db.Query(sqlGet, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = repo.loadPermissions(roles)
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRole(row rowScanner) (*models.Role, error) {
+	var role models.Role
+	var levelAccess sql.NullInt16
+	err := row.Scan(
+		&role.ID,
+		&role.Name,
+		&levelAccess,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if levelAccess.Valid {
+		roleLevelAccess := models.LevelAccess(levelAccess.Int16)
+		role.LevelAccess = &roleLevelAccess
+	}
+	role.Permissions = make(models.Permissions, 0)
+	return &role, nil
+}
+
+func (repo *RoleRepositoryPG) loadPermissions(roles []*models.Role) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	rolesById := make(map[int64]*models.Role)
+	roleIds := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		rolesById[role.ID] = role
+		roleIds = append(roleIds, role.ID)
+	}
+
+	sqlGet := `
+		SELECT role_id, permission
+		FROM users.role_permission
+		WHERE role_id = ANY($1)
+		ORDER BY permission;
+	`
+	rows, err := repo.db.Query(sqlGet, pq.Array(roleIds))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var roleId int64
+		var permission models.Permission
+		err := rows.Scan(&roleId, &permission)
+		if err != nil {
+			return err
+		}
+		rolesById[roleId].Permissions = append(rolesById[roleId].Permissions, permission)
+	}
+	return rows.Err()
+}
+
+func (repo *RoleRepositoryPG) AssignRoleToUser(userId, roleId int64) error {
+	sqlInsert := `
+		INSERT INTO users.user_role (user_id, role_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`
+	_, err := repo.db.Exec(sqlInsert, userId, roleId)
+	return err
+}
+
+func (repo *RoleRepositoryPG) UnassignRoleFromUser(userId, roleId int64) error {
+	sqlDelete := `
+		DELETE FROM users.user_role
+		WHERE user_id=$1 AND role_id=$2;
+	`
+	_, err := repo.db.Exec(sqlDelete, userId, roleId)
+	return err
+}
+
+// GetUserPermissions merges the default role of the level access with the assigned roles
+func (repo *RoleRepositoryPG) GetUserPermissions(userId int64, levelAccess models.LevelAccess) (models.Permissions, error) {
+	var permissions = make(models.Permissions, 0)
+	sqlGet := `
+		SELECT DISTINCT rp.permission
+		FROM users.role_permission rp
+		INNER JOIN users.role r ON r.id = rp.role_id
+		WHERE
+			r.level_access=$2 OR
+			r.id IN (SELECT role_id FROM users.user_role WHERE user_id=$1)
+		ORDER BY rp.permission;
+	`
+	rows, err := repo.db.Query(sqlGet, userId, levelAccess)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var permission models.Permission
+		err := rows.Scan(&permission)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, rows.Err()
+}
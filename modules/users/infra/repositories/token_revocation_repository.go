@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"api/modules/users/models"
+)
+
+type TokenRevocationRepositoryPG struct {
+	db *sql.DB
+}
+
+func NewTokenRevocationRepository(db *sql.DB) models.TokenRevocationRepository {
+	return &TokenRevocationRepositoryPG{db}
+}
+
+func (repo *TokenRevocationRepositoryPG) InsertRevokedToken(tokenId string, userId int64, expiredAt time.Time) error {
+	sqlInsert := `
+		INSERT INTO users.revoked_token (token_id, user_id, expired_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (token_id) DO NOTHING;
+	`
+	args := []interface{}{tokenId, userId, expiredAt.UTC()}
+	_, err := repo.db.Exec(sqlInsert, args...)
+	return err
+}
+
+func (repo *TokenRevocationRepositoryPG) IsTokenRevoked(tokenId string) (bool, error) {
+	var revoked bool
+	sqlGet := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM users.revoked_token
+			WHERE token_id=$1
+		);
+	`
+	row := repo.db.QueryRow(sqlGet, tokenId)
+	if row.Err() != nil {
+		return false, row.Err()
+	}
+	err := row.Scan(&revoked)
+	if err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
+
+func (repo *TokenRevocationRepositoryPG) DeleteExpiredRevokedTokens(now time.Time) (int64, error) {
+	sqlDelete := `
+		DELETE FROM users.revoked_token
+		WHERE expired_at < $1;
+	`
+	result, err := repo.db.Exec(sqlDelete, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (repo *TokenRevocationRepositoryPG) UpsertUserTokensRevokedBefore(userId int64, before time.Time) error {
+	sqlUpsert := `
+		INSERT INTO users.user_token_revocation (user_id, revoked_before)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET revoked_before=GREATEST(users.user_token_revocation.revoked_before, EXCLUDED.revoked_before);
+	`
+	args := []interface{}{userId, before.UTC()}
+	_, err := repo.db.Exec(sqlUpsert, args...)
+	return err
+}
+
+func (repo *TokenRevocationRepositoryPG) GetUserTokensRevokedBefore(userId int64) (*time.Time, error) {
+	var revokedBefore time.Time
+	sqlGet := `
+		SELECT revoked_before
+		FROM users.user_token_revocation
+		WHERE user_id=$1;
+	`
+	row := repo.db.QueryRow(sqlGet, userId)
+	if row.Err() != nil {
+		return nil, row.Err()
+	}
+	err := row.Scan(&revokedBefore)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revokedBefore, nil
+}
+
+func (repo *TokenRevocationRepositoryPG) DeleteUserTokensRevokedBefore(before time.Time) (int64, error) {
+	sqlDelete := `
+		DELETE FROM users.user_token_revocation
+		WHERE revoked_before < $1;
+	`
+	result, err := repo.db.Exec(sqlDelete, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -18,14 +18,14 @@ func NewUserRepository(db *sql.DB) models.UserRepository {
 	return &UserRepositoryPG{db}
 }
 
-func (repo *UserRepositoryPG) InsertUser(name, username, password, email string, levelAccess models.LevelAccess) (*models.User, error) {
+func (repo *UserRepositoryPG) InsertUser(name, username, password, email string, levelAccess models.LevelAccess, mustChangePassword bool) (*models.User, error) {
 	var user models.User
 	sqlInsert := `
-		INSERT INTO users.user (name, email, username, password, level_access)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users.user (name, email, username, password, level_access, must_change_password)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, created_at, updated_at;
 	`
-	args := []interface{}{name, email, username, password, levelAccess}
+	args := []interface{}{name, email, username, password, levelAccess, mustChangePassword}
 	row := repo.db.QueryRow(sqlInsert, args...)
 	if row.Err() != nil {
 		return nil, row.Err()
@@ -38,6 +38,7 @@ func (repo *UserRepositoryPG) InsertUser(name, username, password, email string,
 	user.Email = email
 	user.Username = username
 	user.LevelAccess = levelAccess
+	user.MustChangePassword = mustChangePassword
 	return &user, nil
 }
 
@@ -59,6 +60,35 @@ func (repo *UserRepositoryPG) UpdateUser(id int64, name, username, password, ema
 	return err
 }
 
+func (repo *UserRepositoryPG) UpdatePassword(id int64, password string, mustChangePassword bool) error {
+	now := time.Now().UTC()
+	sqlUpdate := `
+		UPDATE users.user
+		SET 
+			password=$2,
+			must_change_password=$3,
+			updated_at=$4
+		WHERE id=$1
+	`
+	args := []interface{}{id, password, mustChangePassword, now}
+	_, err := repo.db.Exec(sqlUpdate, args...)
+	return err
+}
+
+func (repo *UserRepositoryPG) UpdateDisabled(id int64, disabled bool) error {
+	now := time.Now().UTC()
+	sqlUpdate := `
+		UPDATE users.user
+		SET 
+			disabled=$2,
+			updated_at=$3
+		WHERE id=$1
+	`
+	args := []interface{}{id, disabled, now}
+	_, err := repo.db.Exec(sqlUpdate, args...)
+	return err
+}
+
 func (repo *UserRepositoryPG) DeleteUser(id int64) error {
 	sqlDelete := `
 		DELETE FROM users.user
@@ -73,7 +103,7 @@ func (repo *UserRepositoryPG) GetUser(id int64) (*models.User, error) {
 	var bufferImage = []byte{}
 
 	sqlGet := `
-		SELECT id, name, email, username, password, level_access, created_at, updated_at, photo
+		SELECT id, name, email, username, password, level_access, created_at, updated_at, photo, must_change_password, disabled
 		FROM users.user
 		WHERE id=$1;
 	`
@@ -93,6 +123,8 @@ func (repo *UserRepositoryPG) GetUser(id int64) (*models.User, error) {
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&bufferImage,
+		&user.MustChangePassword,
+		&user.Disabled,
 	)
 
 	if err != nil {
@@ -115,7 +147,7 @@ func (repo *UserRepositoryPG) GetUserByEmail(email string) (*models.User, error)
 	var bufferImage = []byte{}
 
 	sqlGet := `
-		SELECT id, name, email, username, password, level_access, created_at, updated_at, photo
+		SELECT id, name, email, username, password, level_access, created_at, updated_at, photo, must_change_password, disabled
 		FROM users.user
 		WHERE LOWER(email)=$1;
 	`
@@ -136,6 +168,8 @@ func (repo *UserRepositoryPG) GetUserByEmail(email string) (*models.User, error)
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&bufferImage,
+		&user.MustChangePassword,
+		&user.Disabled,
 	)
 
 	if err != nil {
@@ -158,7 +192,7 @@ func (repo *UserRepositoryPG) GetUserByUsername(username string) (*models.User,
 	var bufferImage = []byte{}
 
 	sqlGet := `
-		SELECT id, name, email, username, password, level_access, created_at, updated_at, photo
+		SELECT id, name, email, username, password, level_access, created_at, updated_at, photo, must_change_password, disabled
 		FROM users.user
 		WHERE LOWER(username)=$1;
 	`
@@ -179,6 +213,8 @@ func (repo *UserRepositoryPG) GetUserByUsername(username string) (*models.User,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&bufferImage,
+		&user.MustChangePassword,
+		&user.Disabled,
 	)
 
 	if err != nil {
@@ -199,7 +235,7 @@ func (repo *UserRepositoryPG) GetUserByUsername(username string) (*models.User,
 func (repo *UserRepositoryPG) GetAllUser() ([]*models.User, error) {
 	var todos = make([]*models.User, 0)
 	sqlGet := `
-		SELECT id, name, email, username, password, level_access, created_at, updated_at, photo
+		SELECT id, name, email, username, password, level_access, created_at, updated_at, photo, must_change_password, disabled
 		FROM users.user
 		ORDER BY created_at DESC;
 	`
@@ -222,6 +258,8 @@ func (repo *UserRepositoryPG) GetAllUser() ([]*models.User, error) {
 			&user.CreatedAt,
 			&user.UpdatedAt,
 			&bufferImage,
+			&user.MustChangePassword,
+			&user.Disabled,
 		)
 		if err != nil {
 			return nil, nil
@@ -56,3 +56,8 @@ func (maker *JWTMaker) VerifyToken(token string) (*models.TokenPayload, error) {
 	}
 	return payload, nil
 }
+
+// KeySet is always empty, the symmetric secret key can't be published
+func (maker *JWTMaker) KeySet() models.JSONWebKeySet {
+	return models.JSONWebKeySet{Keys: make([]models.JSONWebKey, 0)}
+}
@@ -0,0 +1,205 @@
+package tokenjwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+
+	"api/modules/users/models"
+)
+
+var (
+	ErrUnsupportedKey = errors.New("unsupported key, use RSA or Ed25519")
+	ErrKeyIdEmpty     = errors.New("key id is empty")
+)
+
+// SigningKey is a key from the keyring, verify only keys have no private key
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// Keyring holds one active key used to sign and the keys still accepted on verify
+type Keyring struct {
+	activeKey *SigningKey
+	keys      map[string]*SigningKey
+	ordered   []*SigningKey
+}
+
+func NewKeyring(activeKey *SigningKey, verifyKeys ...*SigningKey) (*Keyring, error) {
+	if activeKey == nil || activeKey.PrivateKey == nil {
+		return nil, errors.New("active key needs a private key")
+	}
+
+	keyring := &Keyring{activeKey, map[string]*SigningKey{}, make([]*SigningKey, 0)}
+	for _, key := range append([]*SigningKey{activeKey}, verifyKeys...) {
+		if key.ID == "" {
+			return nil, ErrKeyIdEmpty
+		}
+		if _, exists := keyring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicated key id %q", key.ID)
+		}
+		keyring.keys[key.ID] = key
+		keyring.ordered = append(keyring.ordered, key)
+	}
+	return keyring, nil
+}
+
+// LoadKeyringFromFiles reads the active private key and the verify keys, these
+// are informed like "kid=path/to/public.pem,kid2=path/to/public2.pem"
+func LoadKeyringFromFiles(activeKeyId, activeKeyFile, verifyKeyFiles string) (*Keyring, error) {
+	activeKey, err := LoadPrivateKeyFile(activeKeyId, activeKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	verifyKeys := make([]*SigningKey, 0)
+	for _, entry := range strings.Split(verifyKeyFiles, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		entrySplited := strings.SplitN(entry, "=", 2)
+		if len(entrySplited) != 2 {
+			return nil, fmt.Errorf("verify key %q should be kid=path", entry)
+		}
+		verifyKey, err := LoadPublicKeyFile(entrySplited[0], entrySplited[1])
+		if err != nil {
+			return nil, err
+		}
+		verifyKeys = append(verifyKeys, verifyKey)
+	}
+
+	return NewKeyring(activeKey, verifyKeys...)
+}
+
+func LoadPrivateKeyFile(keyId, path string) (*SigningKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var privateKey interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+	return newSigningKey(keyId, signer, signer.Public())
+}
+
+// LoadPublicKeyFile also accepts a private key file, only its public part is kept
+func LoadPublicKeyFile(keyId, path string) (*SigningKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newSigningKey(keyId, nil, publicKey)
+	case "RSA PUBLIC KEY":
+		publicKey, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newSigningKey(keyId, nil, publicKey)
+	}
+
+	signingKey, err := LoadPrivateKeyFile(keyId, path)
+	if err != nil {
+		return nil, err
+	}
+	signingKey.PrivateKey = nil
+	return signingKey, nil
+}
+
+func readPEMFile(path string) (*pem.Block, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found on %s", path)
+	}
+	return block, nil
+}
+
+func newSigningKey(keyId string, privateKey crypto.Signer, publicKey crypto.PublicKey) (*SigningKey, error) {
+	if keyId == "" {
+		return nil, ErrKeyIdEmpty
+	}
+
+	var method jwt.SigningMethod
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, ErrUnsupportedKey
+	}
+	return &SigningKey{keyId, method, privateKey, publicKey}, nil
+}
+
+func (keyring *Keyring) ActiveKey() *SigningKey {
+	return keyring.activeKey
+}
+
+func (keyring *Keyring) Key(keyId string) (*SigningKey, bool) {
+	key, ok := keyring.keys[keyId]
+	return key, ok
+}
+
+func (keyring *Keyring) KeySet() models.JSONWebKeySet {
+	keySet := models.JSONWebKeySet{Keys: make([]models.JSONWebKey, 0, len(keyring.ordered))}
+	for _, key := range keyring.ordered {
+		keySet.Keys = append(keySet.Keys, key.toJSONWebKey())
+	}
+	return keySet
+}
+
+func (key *SigningKey) toJSONWebKey() models.JSONWebKey {
+	jsonWebKey := models.JSONWebKey{
+		Kid: key.ID,
+		Use: "sig",
+		Alg: key.Method.Alg(),
+	}
+	switch publicKey := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jsonWebKey.Kty = "RSA"
+		jsonWebKey.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jsonWebKey.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jsonWebKey.Kty = "OKP"
+		jsonWebKey.Crv = "Ed25519"
+		jsonWebKey.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+	return jsonWebKey
+}
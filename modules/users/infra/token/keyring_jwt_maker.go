@@ -0,0 +1,68 @@
+package tokenjwt
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt"
+
+	"api/modules/users/models"
+)
+
+const keyIdHeader = "kid"
+
+// KeyringJWTMaker signs with the active key of the keyring (RS256 or EdDSA)
+// and verifies with any key of the keyring found by the kid header
+type KeyringJWTMaker struct {
+	keyring *Keyring
+}
+
+func NewKeyringJWTMaker(keyring *Keyring) *KeyringJWTMaker {
+	return &KeyringJWTMaker{keyring}
+}
+
+func (maker *KeyringJWTMaker) CreateToken(payload *models.TokenPayload) (string, error) {
+	activeKey := maker.keyring.ActiveKey()
+	jwtToken := jwt.NewWithClaims(activeKey.Method, payload)
+	jwtToken.Header[keyIdHeader] = activeKey.ID
+	token, err := jwtToken.SignedString(activeKey.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (maker *KeyringJWTMaker) VerifyToken(token string) (*models.TokenPayload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		keyId, ok := token.Header[keyIdHeader].(string)
+		if !ok {
+			return nil, models.ErrInvalidToken
+		}
+		key, ok := maker.keyring.Key(keyId)
+		if !ok {
+			return nil, models.ErrInvalidToken
+		}
+		// the algorithm is fixed by the key, never by the token header
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, models.ErrInvalidToken
+		}
+		return key.PublicKey, nil
+	}
+	jwtToken, err := jwt.ParseWithClaims(token, &models.TokenPayload{}, keyFunc)
+	if err != nil {
+		verr, ok := err.(*jwt.ValidationError)
+		if ok && errors.Is(verr.Inner, models.ErrExpiredToken) {
+			return nil, models.ErrExpiredToken
+		}
+		return nil, models.ErrInvalidToken
+	}
+
+	payload, ok := jwtToken.Claims.(*models.TokenPayload)
+	if !ok {
+		return nil, models.ErrInvalidToken
+	}
+	return payload, nil
+}
+
+func (maker *KeyringJWTMaker) KeySet() models.JSONWebKeySet {
+	return maker.keyring.KeySet()
+}
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"api/modules/users/models"
 	"api/modules/users/usecases"
 	"fmt"
 	"net/http"
@@ -12,23 +13,38 @@ import (
 const (
 	UserId      = "userId"
 	LevelAccess = "levelAccess"
+	Permissions = "permissions"
+	Token       = "token"
 )
 
 type AuthorizationMiddleware interface {
 	Authorize() gin.HandlerFunc
+	// AuthorizePendingPasswordChange also accepts tokens of users that
+	// must change the password, use it only on the routes to do so
+	AuthorizePendingPasswordChange() gin.HandlerFunc
 }
 
 type AuthorizationMiddlewareGin struct {
-	tokenManager usecases.TokenManager
+	tokenManager    usecases.TokenManager
+	tokenRevocation usecases.TokenRevocation
 }
 
 func NewAuthorizationMiddleware(
 	tokenManager usecases.TokenManager,
+	tokenRevocation usecases.TokenRevocation,
 ) AuthorizationMiddleware {
-	return &AuthorizationMiddlewareGin{tokenManager}
+	return &AuthorizationMiddlewareGin{tokenManager, tokenRevocation}
 }
 
 func (middleware *AuthorizationMiddlewareGin) Authorize() gin.HandlerFunc {
+	return middleware.authorize(false)
+}
+
+func (middleware *AuthorizationMiddlewareGin) AuthorizePendingPasswordChange() gin.HandlerFunc {
+	return middleware.authorize(true)
+}
+
+func (middleware *AuthorizationMiddlewareGin) authorize(allowPendingPasswordChange bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// split string header Authorization: Authorization: Bearer <token>
 		authorizationHeader := c.Request.Header.Get("Authorization")
@@ -54,9 +70,51 @@ func (middleware *AuthorizationMiddlewareGin) Authorize() gin.HandlerFunc {
 			return
 		}
 
+		// check if token was revoked by logout
+		revoked, err := middleware.tokenRevocation.IsRevoked(payloadToken)
+		if err != nil {
+			// TODO: make log
+			fmt.Println(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "token was revoked"})
+			return
+		}
+
+		if payloadToken.MustChangePassword && !allowPendingPasswordChange {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "you need to change your password"})
+			return
+		}
+
 		// add userid and level access from payload token
 		c.Set(UserId, payloadToken.UserId)
 		c.Set(LevelAccess, payloadToken.LevelAccess)
+		c.Set(Permissions, payloadToken.Permissions)
+		c.Set(Token, token)
+		c.Next()
+	}
+}
+
+// RequirePermission should run after Authorize, the request is forbidden
+// when the token doesn't carry every one of the permissions
+func RequirePermission(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissionsValue, exists := c.Get(Permissions)
+		if !exists {
+			fmt.Println("need authorize middleware before require permission")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "server error"})
+			return
+		}
+		tokenPermissions := permissionsValue.(models.Permissions)
+
+		for _, permission := range permissions {
+			if !tokenPermissions.Has(permission) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "you don't have authorization"})
+				return
+			}
+		}
 		c.Next()
 	}
 }
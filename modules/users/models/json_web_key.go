@@ -0,0 +1,22 @@
+package models
+
+// JSONWebKey is the public part of a signing key as described on RFC 7517
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// KeySetProvider is implemented by the token makers that can publish their public keys
+type KeySetProvider interface {
+	KeySet() JSONWebKeySet
+}
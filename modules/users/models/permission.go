@@ -0,0 +1,51 @@
+package models
+
+import "errors"
+
+var (
+	ErrPermissionIsInvalid = errors.New("permission is invalid")
+)
+
+type Permission string
+
+const (
+	TodoReadPermission     Permission = "todo:read"
+	TodoWritePermission    Permission = "todo:write"
+	TodoDeletePermission   Permission = "todo:delete"
+	StatusReadPermission   Permission = "status:read"
+	StatusWritePermission  Permission = "status:write"
+	StatusDeletePermission Permission = "status:delete"
+	UserReadPermission     Permission = "user:read"
+	UserAdminPermission    Permission = "user:admin"
+)
+
+var AllPermissions = []Permission{
+	TodoReadPermission,
+	TodoWritePermission,
+	TodoDeletePermission,
+	StatusReadPermission,
+	StatusWritePermission,
+	StatusDeletePermission,
+	UserReadPermission,
+	UserAdminPermission,
+}
+
+func (p Permission) Valid() error {
+	for _, permission := range AllPermissions {
+		if permission == p {
+			return nil
+		}
+	}
+	return ErrPermissionIsInvalid
+}
+
+type Permissions []Permission
+
+func (permissions Permissions) Has(permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, log in again")
+)
+
+// RefreshToken is an opaque token stored hashed, every rotation keeps the
+// same FamilyId so a reused token can revoke all the tokens derived from it
+type RefreshToken struct {
+	ID        int64
+	UserId    int64
+	FamilyId  string
+	TokenHash string
+	ExpiredAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+type RefreshTokenRepository interface {
+	InsertRefreshToken(userId int64, familyId, tokenHash string, expiredAt time.Time) (*RefreshToken, error)
+	GetRefreshTokenByHash(tokenHash string) (*RefreshToken, error)
+	MarkRefreshTokenUsed(id int64) (bool, error)
+	RevokeRefreshTokenFamily(familyId string) error
+	RevokeUserRefreshTokens(userId int64) error
+	DeleteExpiredRefreshTokens(now time.Time) (int64, error)
+}
+
+func (token *RefreshToken) Expired() bool {
+	return time.Now().After(token.ExpiredAt)
+}
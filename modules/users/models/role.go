@@ -0,0 +1,55 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRoleNameIsSmall = errors.New("role name is small")
+	ErrRoleNameIsLarge = errors.New("role name is large")
+)
+
+// Role groups permissions, the default roles have the LevelAccess they
+// replace and are granted to every user with that level access
+type Role struct {
+	ID          int64        `json:"id"`
+	Name        string       `json:"name"`
+	LevelAccess *LevelAccess `json:"levelAccess"`
+	Permissions Permissions  `json:"permissions"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+}
+
+type RoleRepository interface {
+	InsertRole(name string, permissions Permissions) (*Role, error)
+	UpdateRole(id int64, name string, permissions Permissions) error
+	DeleteRole(id int64) error
+	GetRole(id int64) (*Role, error)
+	GetRoleByName(name string) (*Role, error)
+	GetAllRole() ([]*Role, error)
+
+	AssignRoleToUser(userId, roleId int64) error
+	UnassignRoleFromUser(userId, roleId int64) error
+	GetUserRoles(userId int64) ([]*Role, error)
+	GetUserPermissions(userId int64, levelAccess LevelAccess) (Permissions, error)
+}
+
+func (r *Role) Valid() error {
+	if len(r.Name) < 2 {
+		return ErrRoleNameIsSmall
+	}
+	if len(r.Name) > 255 {
+		return ErrRoleNameIsLarge
+	}
+	for _, permission := range r.Permissions {
+		if err := permission.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Role) IsDefault() bool {
+	return r.LevelAccess != nil
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 )
@@ -10,7 +12,10 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
-const DurationTimeDefault = time.Hour * 24 * 7
+const (
+	AccessTokenDuration  = time.Minute * 15
+	RefreshTokenDuration = time.Hour * 24 * 30
+)
 
 type TokenMaker interface {
 	CreateToken(payload *TokenPayload) (string, error)
@@ -18,21 +23,43 @@ type TokenMaker interface {
 }
 
 type TokenPayload struct {
+	ID          string `json:"jti"`
 	UserId      int64
 	LevelAccess LevelAccess
-	IssuedAt    time.Time
-	ExpiredAt   time.Time
+	Permissions Permissions
+	// MustChangePassword restricts the token to the password change
+	MustChangePassword bool `json:",omitempty"`
+	IssuedAt           time.Time
+	ExpiredAt          time.Time
 }
 
-func NewTokenPayload(userId int64, levelAccess LevelAccess, duration time.Duration) *TokenPayload {
+func NewTokenPayload(userId int64, levelAccess LevelAccess, permissions Permissions, mustChangePassword bool, duration time.Duration) (*TokenPayload, error) {
+	tokenId, err := newTokenId()
+	if err != nil {
+		return nil, err
+	}
+
 	payload := &TokenPayload{
+		tokenId,
 		userId,
 		levelAccess,
+		permissions,
+		mustChangePassword,
 		time.Now(),
 		time.Now().Add(duration),
 	}
 
-	return payload
+	return payload, nil
+}
+
+// newTokenId makes the random jti used to revoke a single token
+func newTokenId() (string, error) {
+	buffer := make([]byte, 16)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
 }
 
 func (payload *TokenPayload) Valid() error {
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type TokenRevocationRepository interface {
+	InsertRevokedToken(tokenId string, userId int64, expiredAt time.Time) error
+	IsTokenRevoked(tokenId string) (bool, error)
+	DeleteExpiredRevokedTokens(now time.Time) (int64, error)
+
+	UpsertUserTokensRevokedBefore(userId int64, before time.Time) error
+	GetUserTokensRevokedBefore(userId int64) (*time.Time, error)
+	DeleteUserTokensRevokedBefore(before time.Time) (int64, error)
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/mail"
 	"time"
 )
@@ -27,6 +28,10 @@ type User struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Photo       bytes.Buffer
+	// MustChangePassword blocks every route but the password change
+	MustChangePassword bool
+	// Disabled users can't login
+	Disabled bool
 }
 
 type UserSafeHttp struct {
@@ -37,11 +42,18 @@ type UserSafeHttp struct {
 	LevelAccess LevelAccess `json:"levelAccess"`
 	CreatedAt   time.Time   `json:"createdAt"`
 	UpdatedAt   time.Time   `json:"updatedAt"`
+
+	MustChangePassword bool `json:"mustChangePassword"`
+	Disabled           bool `json:"disabled"`
+	// PhotoUrl is empty when the user has no photo
+	PhotoUrl string `json:"photoUrl"`
 }
 
 type UserRepository interface {
-	InsertUser(name, username, password, email string, levelAccess LevelAccess) (*User, error)
+	InsertUser(name, username, password, email string, levelAccess LevelAccess, mustChangePassword bool) (*User, error)
 	UpdateUser(id int64, name, username, password, email string, levelAccess LevelAccess) error
+	UpdatePassword(id int64, password string, mustChangePassword bool) error
+	UpdateDisabled(id int64, disabled bool) error
 	DeleteUser(id int64) error
 	GetUser(id int64) (*User, error)
 	GetUserByEmail(email string) (*User, error)
@@ -64,7 +76,7 @@ func NewUser(
 	updatedAt time.Time,
 	photo bytes.Buffer,
 ) (*User, error) {
-	user := &User{id, name, username, password, email, levelAccess, createdAt, updatedAt, photo}
+	user := &User{id, name, username, password, email, levelAccess, createdAt, updatedAt, photo, false, false}
 	err := user.Valid()
 	if err != nil {
 		return nil, err
@@ -109,5 +121,16 @@ func (u *User) ToSafeHttp() *UserSafeHttp {
 		LevelAccess: u.LevelAccess,
 		CreatedAt:   u.CreatedAt,
 		UpdatedAt:   u.UpdatedAt,
+
+		MustChangePassword: u.MustChangePassword,
+		Disabled:           u.Disabled,
+		PhotoUrl:           u.PhotoUrl(),
+	}
+}
+
+func (u *User) PhotoUrl() string {
+	if u.Photo.Len() == 0 {
+		return ""
 	}
+	return fmt.Sprintf("/users/photo/%d", u.ID)
 }
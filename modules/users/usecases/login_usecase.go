@@ -3,30 +3,43 @@ package usecases
 import (
 	"api/modules/users/dto"
 	"api/modules/users/models"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"time"
 )
 
 var (
-	ErrCredencialsWrong = errors.New("nome de usuário ou senha inválidos")
+	ErrCredencialsWrong     = errors.New("nome de usuário ou senha inválidos")
+	ErrRevokeBeforeInFuture = errors.New("revoke time should not be in the future")
+	ErrUserDisabled         = errors.New("user is disabled")
 )
 
 type LoginUsecase interface {
 	Login(username string, password string) (loginResponse dto.LoginResponse, usecaseError, serverError error)
-	Logout(token string) (tokenInvalid string, usecaseError, serverError error)
+	Refresh(refreshToken string) (loginResponse dto.LoginResponse, usecaseError, serverError error)
+	Logout(token, refreshToken string) (tokenInvalid string, usecaseError, serverError error)
+	LogoutAll(userId int64, before time.Time) (usecaseError, serverError error)
 }
 
 type TokenLoginUsecase struct {
-	userRepository models.UserRepository
-	hashPassword   HashPassword
-	tokenManager   TokenManager
+	userRepository         models.UserRepository
+	roleRepository         models.RoleRepository
+	refreshTokenRepository models.RefreshTokenRepository
+	hashPassword           HashPassword
+	tokenManager           TokenManager
+	tokenRevocation        TokenRevocation
 }
 
 func NewTokenLoginUsecase(
 	userRepository models.UserRepository,
+	roleRepository models.RoleRepository,
+	refreshTokenRepository models.RefreshTokenRepository,
 	hashPassword HashPassword,
 	tokenManager TokenManager,
+	tokenRevocation TokenRevocation,
 ) LoginUsecase {
-	return &TokenLoginUsecase{userRepository, hashPassword, tokenManager}
+	return &TokenLoginUsecase{userRepository, roleRepository, refreshTokenRepository, hashPassword, tokenManager, tokenRevocation}
 }
 
 func (usecase *TokenLoginUsecase) Login(username string, password string) (loginResponse dto.LoginResponse, usecaseError, serverError error) {
@@ -47,23 +60,159 @@ func (usecase *TokenLoginUsecase) Login(username string, password string) (login
 		usecaseError = ErrCredencialsWrong
 		return
 	}
+	if userFound.Disabled {
+		usecaseError = ErrUserDisabled
+		return
+	}
 
-	token, serverError := usecase.tokenManager.CreateToken(
-		userFound.ID,
-		userFound.LevelAccess,
-		models.DurationTimeDefault,
-	)
+	// a new login starts a new refresh token family
+	familyId, serverError := newRefreshTokenFamilyId()
+	if serverError != nil {
+		return
+	}
+
+	loginResponse, serverError = usecase.issueTokens(userFound, familyId)
+	return
+}
+
+// Refresh rotates the refresh token, a token used twice revokes its whole family
+func (usecase *TokenLoginUsecase) Refresh(refreshToken string) (loginResponse dto.LoginResponse, usecaseError, serverError error) {
+	tokenHash := usecase.tokenManager.HashRefreshToken(refreshToken)
+	tokenFound, serverError := usecase.refreshTokenRepository.GetRefreshTokenByHash(tokenHash)
+	if serverError != nil {
+		return
+	}
+	if tokenFound == nil || tokenFound.RevokedAt != nil || tokenFound.Expired() {
+		usecaseError = models.ErrInvalidRefreshToken
+		return
+	}
+	if tokenFound.UsedAt != nil {
+		serverError = usecase.tokenRevocation.RevokeRefreshTokenFamily(tokenFound.FamilyId)
+		if serverError != nil {
+			return
+		}
+		usecaseError = models.ErrRefreshTokenReused
+		return
+	}
+
+	marked, serverError := usecase.refreshTokenRepository.MarkRefreshTokenUsed(tokenFound.ID)
+	if serverError != nil {
+		return
+	}
+	if !marked {
+		// used by a concurrent request between the read and the update
+		serverError = usecase.tokenRevocation.RevokeRefreshTokenFamily(tokenFound.FamilyId)
+		if serverError != nil {
+			return
+		}
+		usecaseError = models.ErrRefreshTokenReused
+		return
+	}
+
+	userFound, serverError := usecase.userRepository.GetUser(tokenFound.UserId)
 	if serverError != nil {
 		return
 	}
+	if userFound == nil {
+		usecaseError = models.ErrInvalidRefreshToken
+		return
+	}
+	if userFound.Disabled {
+		usecaseError = ErrUserDisabled
+		return
+	}
+
+	loginResponse, serverError = usecase.issueTokens(userFound, tokenFound.FamilyId)
+	return
+}
+
+func (usecase *TokenLoginUsecase) issueTokens(user *models.User, familyId string) (loginResponse dto.LoginResponse, err error) {
+	permissions, err := usecase.roleRepository.GetUserPermissions(user.ID, user.LevelAccess)
+	if err != nil {
+		return
+	}
+
+	token, err := usecase.tokenManager.CreateToken(
+		user.ID,
+		user.LevelAccess,
+		permissions,
+		user.MustChangePassword,
+		models.AccessTokenDuration,
+	)
+	if err != nil {
+		return
+	}
+
+	refreshToken, refreshTokenHash, err := usecase.tokenManager.CreateRefreshToken()
+	if err != nil {
+		return
+	}
+	_, err = usecase.refreshTokenRepository.InsertRefreshToken(
+		user.ID,
+		familyId,
+		refreshTokenHash,
+		time.Now().Add(models.RefreshTokenDuration),
+	)
+	if err != nil {
+		return
+	}
+
 	loginResponse = dto.LoginResponse{
-		Token: token,
-		User:  *userFound,
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         *user,
+	}
+	return
+}
+
+// Logout revokes the access token and, when informed, the refresh token family
+func (usecase *TokenLoginUsecase) Logout(token, refreshToken string) (tokenInvalid string, usecaseError, serverError error) {
+	payload, err := usecase.tokenManager.VerifyToken(token)
+	if err != nil {
+		usecaseError = err
+		return
+	}
+
+	serverError = usecase.tokenRevocation.RevokeToken(payload)
+	if serverError != nil {
+		return
+	}
+
+	if refreshToken != "" {
+		tokenHash := usecase.tokenManager.HashRefreshToken(refreshToken)
+		refreshTokenFound, err := usecase.refreshTokenRepository.GetRefreshTokenByHash(tokenHash)
+		if err != nil {
+			serverError = err
+			return
+		}
+		if refreshTokenFound != nil && refreshTokenFound.UserId == payload.UserId {
+			serverError = usecase.tokenRevocation.RevokeRefreshTokenFamily(refreshTokenFound.FamilyId)
+			if serverError != nil {
+				return
+			}
+		}
+	}
+
+	tokenInvalid = token
+	return
+}
+
+// LogoutAll revokes every token issued to the user until before
+func (usecase *TokenLoginUsecase) LogoutAll(userId int64, before time.Time) (usecaseError, serverError error) {
+	if before.After(time.Now()) {
+		usecaseError = ErrRevokeBeforeInFuture
+		return
 	}
 
+	serverError = usecase.tokenRevocation.RevokeAllUserTokens(userId, before)
 	return
 }
 
-func (usecase *TokenLoginUsecase) Logout(token string) (tokenInvalid string, usecaseError, serverError error) {
-	return "", nil, nil
+func newRefreshTokenFamilyId() (string, error) {
+	buffer := make([]byte, 16)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
 }
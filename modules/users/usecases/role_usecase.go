@@ -0,0 +1,194 @@
+package usecases
+
+import (
+	"api/modules/users/models"
+	"errors"
+)
+
+var (
+	ErrRoleNotFound               = errors.New("role not found")
+	ErrRoleAlreadyExists          = errors.New("role already exists")
+	ErrDefaultRoleCannotBeDeleted = errors.New("default role cannot be deleted")
+)
+
+type RoleUsecase interface {
+	CreateRole(name string, permissions models.Permissions) (role *models.Role, usecaseError, serverError error)
+	UpdateRole(id int64, name string, permissions models.Permissions) (usecaseError, serverError error)
+	DeleteRole(id int64) (usecaseError, serverError error)
+	GetRole(id int64) (role *models.Role, usecaseError, serverError error)
+	GetAllRole() (roles []*models.Role, usecaseError, serverError error)
+
+	GetUserRoles(userId int64) (roles []*models.Role, usecaseError, serverError error)
+	AssignRoleToUser(userId, roleId int64) (usecaseError, serverError error)
+	UnassignRoleFromUser(userId, roleId int64) (usecaseError, serverError error)
+	SetUserRole(userId int64, roleName string) (usecaseError, serverError error)
+}
+
+type DBRoleUsecase struct {
+	roleRepository models.RoleRepository
+	userRepository models.UserRepository
+}
+
+func NewRoleUsecase(roleRepository models.RoleRepository, userRepository models.UserRepository) RoleUsecase {
+	return &DBRoleUsecase{roleRepository, userRepository}
+}
+
+func (usecase *DBRoleUsecase) CreateRole(name string, permissions models.Permissions) (role *models.Role, usecaseError, serverError error) {
+	roleToCreate := models.Role{Name: name, Permissions: permissions}
+	usecaseError = roleToCreate.Valid()
+	if usecaseError != nil {
+		return
+	}
+
+	roleFound, serverError := usecase.roleRepository.GetRoleByName(name)
+	if serverError != nil {
+		return
+	}
+	if roleFound != nil {
+		usecaseError = ErrRoleAlreadyExists
+		return
+	}
+
+	role, serverError = usecase.roleRepository.InsertRole(name, permissions)
+	return
+}
+
+func (usecase *DBRoleUsecase) UpdateRole(id int64, name string, permissions models.Permissions) (usecaseError, serverError error) {
+	roleToUpdate := models.Role{ID: id, Name: name, Permissions: permissions}
+	usecaseError = roleToUpdate.Valid()
+	if usecaseError != nil {
+		return
+	}
+
+	roleFound, usecaseError, serverError := usecase.GetRole(id)
+	if usecaseError != nil || serverError != nil {
+		return
+	}
+
+	roleFoundByName, serverError := usecase.roleRepository.GetRoleByName(name)
+	if serverError != nil {
+		return
+	}
+	if roleFoundByName != nil && roleFoundByName.ID != roleFound.ID {
+		usecaseError = ErrRoleAlreadyExists
+		return
+	}
+
+	serverError = usecase.roleRepository.UpdateRole(id, name, permissions)
+	return
+}
+
+func (usecase *DBRoleUsecase) DeleteRole(id int64) (usecaseError, serverError error) {
+	roleFound, usecaseError, serverError := usecase.GetRole(id)
+	if usecaseError != nil || serverError != nil {
+		return
+	}
+	if roleFound.IsDefault() {
+		usecaseError = ErrDefaultRoleCannotBeDeleted
+		return
+	}
+
+	serverError = usecase.roleRepository.DeleteRole(id)
+	return
+}
+
+func (usecase *DBRoleUsecase) GetRole(id int64) (role *models.Role, usecaseError, serverError error) {
+	role, serverError = usecase.roleRepository.GetRole(id)
+	if serverError != nil {
+		return
+	}
+	if role == nil {
+		usecaseError = ErrRoleNotFound
+		return
+	}
+	return
+}
+
+func (usecase *DBRoleUsecase) GetAllRole() (roles []*models.Role, usecaseError, serverError error) {
+	roles, serverError = usecase.roleRepository.GetAllRole()
+	return
+}
+
+func (usecase *DBRoleUsecase) GetUserRoles(userId int64) (roles []*models.Role, usecaseError, serverError error) {
+	userFound, serverError := usecase.userRepository.GetUser(userId)
+	if serverError != nil {
+		return
+	}
+	if userFound == nil {
+		usecaseError = ErrUserNotFound
+		return
+	}
+
+	roles, serverError = usecase.roleRepository.GetUserRoles(userId)
+	return
+}
+
+func (usecase *DBRoleUsecase) AssignRoleToUser(userId, roleId int64) (usecaseError, serverError error) {
+	userFound, serverError := usecase.userRepository.GetUser(userId)
+	if serverError != nil {
+		return
+	}
+	if userFound == nil {
+		usecaseError = ErrUserNotFound
+		return
+	}
+
+	_, usecaseError, serverError = usecase.GetRole(roleId)
+	if usecaseError != nil || serverError != nil {
+		return
+	}
+
+	serverError = usecase.roleRepository.AssignRoleToUser(userId, roleId)
+	return
+}
+
+func (usecase *DBRoleUsecase) UnassignRoleFromUser(userId, roleId int64) (usecaseError, serverError error) {
+	userFound, serverError := usecase.userRepository.GetUser(userId)
+	if serverError != nil {
+		return
+	}
+	if userFound == nil {
+		usecaseError = ErrUserNotFound
+		return
+	}
+
+	serverError = usecase.roleRepository.UnassignRoleFromUser(userId, roleId)
+	return
+}
+
+// SetUserRole changes the level access when the role is a default one,
+// other roles are assigned to the user
+func (usecase *DBRoleUsecase) SetUserRole(userId int64, roleName string) (usecaseError, serverError error) {
+	userFound, serverError := usecase.userRepository.GetUser(userId)
+	if serverError != nil {
+		return
+	}
+	if userFound == nil {
+		usecaseError = ErrUserNotFound
+		return
+	}
+
+	roleFound, serverError := usecase.roleRepository.GetRoleByName(roleName)
+	if serverError != nil {
+		return
+	}
+	if roleFound == nil {
+		usecaseError = ErrRoleNotFound
+		return
+	}
+
+	if roleFound.IsDefault() {
+		serverError = usecase.userRepository.UpdateUser(
+			userFound.ID,
+			userFound.Name,
+			userFound.Username,
+			userFound.Password,
+			userFound.Email,
+			*roleFound.LevelAccess,
+		)
+		return
+	}
+
+	serverError = usecase.roleRepository.AssignRoleToUser(userFound.ID, roleFound.ID)
+	return
+}
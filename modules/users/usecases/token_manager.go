@@ -2,12 +2,21 @@ package usecases
 
 import (
 	"api/modules/users/models"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"time"
 )
 
+const refreshTokenSize = 32
+
 type TokenManager interface {
-	CreateToken(userId int64, levelAccess models.LevelAccess, duration time.Duration) (string, error)
+	CreateToken(userId int64, levelAccess models.LevelAccess, permissions models.Permissions, mustChangePassword bool, duration time.Duration) (string, error)
 	VerifyToken(token string) (*models.TokenPayload, error)
+
+	CreateRefreshToken() (token string, tokenHash string, err error)
+	HashRefreshToken(token string) string
 }
 
 type TokenManagerJwt struct {
@@ -18,8 +27,11 @@ func NewTokenManager(tokenMaker models.TokenMaker) TokenManager {
 	return &TokenManagerJwt{tokenMaker}
 }
 
-func (manager *TokenManagerJwt) CreateToken(userId int64, levelAccess models.LevelAccess, duration time.Duration) (string, error) {
-	payload := models.NewTokenPayload(userId, levelAccess, duration)
+func (manager *TokenManagerJwt) CreateToken(userId int64, levelAccess models.LevelAccess, permissions models.Permissions, mustChangePassword bool, duration time.Duration) (string, error) {
+	payload, err := models.NewTokenPayload(userId, levelAccess, permissions, mustChangePassword, duration)
+	if err != nil {
+		return "", err
+	}
 	token, err := manager.tokenMaker.CreateToken(payload)
 	if err != nil {
 		return "", err
@@ -34,3 +46,19 @@ func (manager *TokenManagerJwt) VerifyToken(token string) (*models.TokenPayload,
 	}
 	return payload, nil
 }
+
+// CreateRefreshToken makes an opaque token, only its hash should be stored
+func (manager *TokenManagerJwt) CreateRefreshToken() (token string, tokenHash string, err error) {
+	buffer := make([]byte, refreshTokenSize)
+	_, err = rand.Read(buffer)
+	if err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(buffer)
+	return token, manager.HashRefreshToken(token), nil
+}
+
+func (manager *TokenManagerJwt) HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
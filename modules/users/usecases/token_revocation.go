@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"api/modules/users/models"
+	"fmt"
+	"time"
+)
+
+type TokenRevocation interface {
+	RevokeToken(payload *models.TokenPayload) error
+	RevokeRefreshTokenFamily(familyId string) error
+	RevokeAllUserTokens(userId int64, before time.Time) error
+	IsRevoked(payload *models.TokenPayload) (bool, error)
+	PurgeExpired() (int64, error)
+	StartPurge(interval time.Duration)
+}
+
+type DBTokenRevocation struct {
+	revocationRepository   models.TokenRevocationRepository
+	refreshTokenRepository models.RefreshTokenRepository
+}
+
+func NewTokenRevocation(
+	revocationRepository models.TokenRevocationRepository,
+	refreshTokenRepository models.RefreshTokenRepository,
+) TokenRevocation {
+	return &DBTokenRevocation{revocationRepository, refreshTokenRepository}
+}
+
+func (revocation *DBTokenRevocation) RevokeToken(payload *models.TokenPayload) error {
+	return revocation.revocationRepository.InsertRevokedToken(payload.ID, payload.UserId, payload.ExpiredAt)
+}
+
+func (revocation *DBTokenRevocation) RevokeRefreshTokenFamily(familyId string) error {
+	return revocation.refreshTokenRepository.RevokeRefreshTokenFamily(familyId)
+}
+
+// RevokeAllUserTokens invalidates every token issued to the user until before,
+// refresh tokens are revoked too so no new access token can be issued
+func (revocation *DBTokenRevocation) RevokeAllUserTokens(userId int64, before time.Time) error {
+	err := revocation.revocationRepository.UpsertUserTokensRevokedBefore(userId, before)
+	if err != nil {
+		return err
+	}
+	return revocation.refreshTokenRepository.RevokeUserRefreshTokens(userId)
+}
+
+func (revocation *DBTokenRevocation) IsRevoked(payload *models.TokenPayload) (bool, error) {
+	revokedBefore, err := revocation.revocationRepository.GetUserTokensRevokedBefore(payload.UserId)
+	if err != nil {
+		return false, err
+	}
+	if revokedBefore != nil && !payload.IssuedAt.After(*revokedBefore) {
+		return true, nil
+	}
+
+	return revocation.revocationRepository.IsTokenRevoked(payload.ID)
+}
+
+// PurgeExpired removes the entries of tokens that already expired by themselves
+func (revocation *DBTokenRevocation) PurgeExpired() (int64, error) {
+	now := time.Now()
+	countTokens, err := revocation.revocationRepository.DeleteExpiredRevokedTokens(now)
+	if err != nil {
+		return 0, err
+	}
+
+	// no token issued before this moment is still alive
+	countUsers, err := revocation.revocationRepository.DeleteUserTokensRevokedBefore(now.Add(-models.AccessTokenDuration))
+	if err != nil {
+		return 0, err
+	}
+
+	countRefreshTokens, err := revocation.refreshTokenRepository.DeleteExpiredRefreshTokens(now)
+	if err != nil {
+		return 0, err
+	}
+	return countTokens + countUsers + countRefreshTokens, nil
+}
+
+func (revocation *DBTokenRevocation) StartPurge(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			_, err := revocation.PurgeExpired()
+			if err != nil {
+				// TODO: make log
+				fmt.Println(err)
+			}
+		}
+	}()
+}
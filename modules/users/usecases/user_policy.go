@@ -0,0 +1,91 @@
+package usecases
+
+import (
+	"api/modules/users/models"
+	"errors"
+)
+
+var (
+	ErrForbidden = errors.New("you don't have authorization")
+)
+
+// Actor is the authenticated user performing a request
+type Actor struct {
+	UserId      int64
+	LevelAccess models.LevelAccess
+	Permissions models.Permissions
+}
+
+func (actor Actor) isSelf(userId int64) bool {
+	return actor.UserId == userId
+}
+
+// UserPolicy decides what an actor can do with the user accounts.
+// Every method returns ErrForbidden when the action is not allowed.
+type UserPolicy interface {
+	CanGetUser(actor Actor, userId int64) error
+	CanGetAllUser(actor Actor) error
+	CanUpdateUser(actor Actor, userId int64) error
+	CanChangeLevelAccess(actor Actor, user *models.User, levelAccess models.LevelAccess) error
+	CanDeleteUser(actor Actor, userId int64) error
+	CanChangePassword(actor Actor, userId int64) error
+	CanUpdatePhotoUser(actor Actor, userId int64) error
+}
+
+type PermissionUserPolicy struct{}
+
+func NewUserPolicy() UserPolicy {
+	return &PermissionUserPolicy{}
+}
+
+func (policy *PermissionUserPolicy) CanGetUser(actor Actor, userId int64) error {
+	if actor.isSelf(userId) || actor.Permissions.Has(models.UserReadPermission) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+func (policy *PermissionUserPolicy) CanGetAllUser(actor Actor) error {
+	if actor.Permissions.Has(models.UserReadPermission) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+func (policy *PermissionUserPolicy) CanUpdateUser(actor Actor, userId int64) error {
+	if actor.isSelf(userId) || actor.Permissions.Has(models.UserAdminPermission) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanChangeLevelAccess only lets admins change roles, keeping the same
+// level access is allowed for everyone that can update the user
+func (policy *PermissionUserPolicy) CanChangeLevelAccess(actor Actor, user *models.User, levelAccess models.LevelAccess) error {
+	if user.LevelAccess == levelAccess || actor.Permissions.Has(models.UserAdminPermission) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+func (policy *PermissionUserPolicy) CanDeleteUser(actor Actor, userId int64) error {
+	if actor.isSelf(userId) || actor.Permissions.Has(models.UserAdminPermission) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanChangePassword is self service only, the old password is required
+func (policy *PermissionUserPolicy) CanChangePassword(actor Actor, userId int64) error {
+	if actor.isSelf(userId) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+func (policy *PermissionUserPolicy) CanUpdatePhotoUser(actor Actor, userId int64) error {
+	if actor.isSelf(userId) || actor.Permissions.Has(models.UserAdminPermission) {
+		return nil
+	}
+	return ErrForbidden
+}
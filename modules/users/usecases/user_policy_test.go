@@ -0,0 +1,157 @@
+package usecases_test
+
+import (
+	"api/modules/users/controllers"
+	"api/modules/users/middlewares"
+	"api/modules/users/models"
+	"api/modules/users/usecases"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	selfId  int64 = 1
+	otherId int64 = 2
+)
+
+var (
+	basicPermissions = models.Permissions{
+		models.TodoReadPermission, models.TodoWritePermission, models.TodoDeletePermission,
+		models.StatusReadPermission, models.StatusWritePermission, models.StatusDeletePermission,
+	}
+	managerPermissions = append(append(models.Permissions{}, basicPermissions...), models.UserReadPermission)
+	adminPermissions   = append(append(models.Permissions{}, managerPermissions...), models.UserAdminPermission)
+
+	basicActor   = usecases.Actor{UserId: selfId, LevelAccess: models.BasicLevelAccess, Permissions: basicPermissions}
+	managerActor = usecases.Actor{UserId: selfId, LevelAccess: models.ManagerLevelAccess, Permissions: managerPermissions}
+	adminActor   = usecases.Actor{UserId: selfId, LevelAccess: models.AdminLevelAccess, Permissions: adminPermissions}
+)
+
+// policyCase is an actor acting on the user target, allowed says if the
+// policy lets it
+type policyCase struct {
+	name    string
+	actor   usecases.Actor
+	target  int64
+	allowed bool
+}
+
+func checkPolicy(t *testing.T, cases []policyCase, can func(actor usecases.Actor, userId int64) error) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := can(tc.actor, tc.target)
+			if tc.allowed && err != nil {
+				t.Fatalf("expected allowed, got %v", err)
+			}
+			if !tc.allowed && err != usecases.ErrForbidden {
+				t.Fatalf("expected ErrForbidden, got %v", err)
+			}
+		})
+	}
+}
+
+// selfOrAdminCases are the actions on an account by its owner or an admin
+func selfOrAdminCases() []policyCase {
+	return []policyCase{
+		{"self", basicActor, selfId, true},
+		{"other basic", basicActor, otherId, false},
+		{"manager", managerActor, otherId, false},
+		{"admin", adminActor, otherId, true},
+	}
+}
+
+func TestCanGetUser(t *testing.T) {
+	policy := usecases.NewUserPolicy()
+	checkPolicy(t, []policyCase{
+		{"self", basicActor, selfId, true},
+		{"other basic", basicActor, otherId, false},
+		{"manager", managerActor, otherId, true},
+		{"admin", adminActor, otherId, true},
+	}, policy.CanGetUser)
+}
+
+func TestCanGetAllUser(t *testing.T) {
+	policy := usecases.NewUserPolicy()
+	checkPolicy(t, []policyCase{
+		{"self", basicActor, selfId, false},
+		{"other basic", basicActor, otherId, false},
+		{"manager", managerActor, otherId, true},
+		{"admin", adminActor, otherId, true},
+	}, func(actor usecases.Actor, userId int64) error {
+		return policy.CanGetAllUser(actor)
+	})
+}
+
+func TestCanUpdateUser(t *testing.T) {
+	checkPolicy(t, selfOrAdminCases(), usecases.NewUserPolicy().CanUpdateUser)
+}
+
+func TestCanDeleteUser(t *testing.T) {
+	checkPolicy(t, selfOrAdminCases(), usecases.NewUserPolicy().CanDeleteUser)
+}
+
+func TestCanUpdatePhotoUser(t *testing.T) {
+	checkPolicy(t, selfOrAdminCases(), usecases.NewUserPolicy().CanUpdatePhotoUser)
+}
+
+func TestCanChangePassword(t *testing.T) {
+	checkPolicy(t, []policyCase{
+		{"self", basicActor, selfId, true},
+		{"other basic", basicActor, otherId, false},
+		{"manager", managerActor, otherId, false},
+		{"admin", adminActor, otherId, false},
+	}, usecases.NewUserPolicy().CanChangePassword)
+}
+
+func TestCanChangeLevelAccess(t *testing.T) {
+	policy := usecases.NewUserPolicy()
+	basicUser := &models.User{ID: otherId, LevelAccess: models.BasicLevelAccess}
+	cases := []struct {
+		name        string
+		actor       usecases.Actor
+		levelAccess models.LevelAccess
+		allowed     bool
+	}{
+		{"self keeps level", basicActor, models.BasicLevelAccess, true},
+		{"self promotes", basicActor, models.AdminLevelAccess, false},
+		{"other basic promotes", basicActor, models.ManagerLevelAccess, false},
+		{"manager promotes", managerActor, models.ManagerLevelAccess, false},
+		{"admin promotes", adminActor, models.AdminLevelAccess, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policy.CanChangeLevelAccess(tc.actor, basicUser, tc.levelAccess)
+			if tc.allowed && err != nil {
+				t.Fatalf("expected allowed, got %v", err)
+			}
+			if !tc.allowed && err != usecases.ErrForbidden {
+				t.Fatalf("expected ErrForbidden, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGetUserForbiddenForOther(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(middlewares.UserId, basicActor.UserId)
+		c.Set(middlewares.LevelAccess, basicActor.LevelAccess)
+		c.Set(middlewares.Permissions, basicActor.Permissions)
+	})
+	// the policy answers before the usecase is called
+	userController := controllers.NewUserController(nil, usecases.NewUserPolicy())
+	router.GET("/users/:id", userController.GetUser())
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/users/2", nil)
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, recorder.Code)
+	}
+}
@@ -2,6 +2,8 @@ package usecases
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"time"
 
@@ -20,13 +22,15 @@ var (
 )
 
 type UserUsecase interface {
-	CreateGenesisUser() (userCreated *models.User, usecaseError, serverError error)
+	CreateGenesisUser(name, username, email, password string) (userCreated *models.User, passwordGenerated string, usecaseError, serverError error)
 	CreateUser(name, username, password, passwordConfirmation, email string) (userCreated *models.User, usecaseError, serverError error)
 	UpdateUser(id int64, name, username, password, email string, levelAccess models.LevelAccess) (usecaseError, serverError error)
 	DeleteUser(id int64) (usecaseError, serverError error)
 	GetUser(id int64) (userFound *models.User, usecaseError, serverError error)
 	GetAllUser() (userFound []*models.User, usecaseError, serverError error)
 	ChangePassword(userId int64, oldPassword, newPassword, newPasswordConfirmation string) (usecaseError, serverError error)
+	ResetPassword(userId int64, password string) (passwordGenerated string, usecaseError, serverError error)
+	DisableUser(id int64) (usecaseError, serverError error)
 
 	UpdatePhotoUser(photo *dto.UpdatePhotoUserDTO) (usecaseError, serverError error)
 	DeletePhotoUser(id int64) (usecaseError, serverError error)
@@ -42,13 +46,32 @@ func NewUserUsecase(userRepository models.UserRepository, hashPassword HashPassw
 	return &DBUserUsecase{userRepository, hashPassword}
 }
 
-func (usecase *DBUserUsecase) CreateGenesisUser() (userCreated *models.User, usecaseError, serverError error) {
-	userCreated, usecaseError = models.NewUser(
+// CreateGenesisUser creates the first admin when there are no users, without
+// password a random one is generated. The password must be changed on first login.
+func (usecase *DBUserUsecase) CreateGenesisUser(name, username, email, password string) (userCreated *models.User, passwordGenerated string, usecaseError, serverError error) {
+	userCount, serverError := usecase.userRepository.CountUser()
+	if serverError != nil {
+		return
+	}
+	if userCount > 0 {
+		usecaseError = ErrAllreadyHaveUsers
+		return
+	}
+
+	if password == "" {
+		passwordGenerated, serverError = generatePassword()
+		if serverError != nil {
+			return
+		}
+		password = passwordGenerated
+	}
+
+	user, usecaseError := models.NewUser(
 		0,
-		"adm",
-		"adm",
-		"123456",
-		"adm@email.com",
+		name,
+		username,
+		password,
+		email,
 		models.AdminLevelAccess,
 		time.Now(),
 		time.Now(),
@@ -58,33 +81,29 @@ func (usecase *DBUserUsecase) CreateGenesisUser() (userCreated *models.User, use
 		return
 	}
 
-	userCount, serverError := usecase.userRepository.CountUser()
-	if serverError != nil {
-		return
-	}
-
-	if userCount > 0 {
-		usecaseError = ErrAllreadyHaveUsers
-		return
-	}
-
-	passwordHashed, serverError := usecase.hashPassword.Hash(userCreated.Password)
+	passwordHashed, serverError := usecase.hashPassword.Hash(user.Password)
 	if serverError != nil {
 		return
 	}
 
 	userCreated, serverError = usecase.userRepository.InsertUser(
-		userCreated.Name,
-		userCreated.Username,
+		user.Name,
+		user.Username,
 		passwordHashed,
-		userCreated.Email,
-		userCreated.LevelAccess,
+		user.Email,
+		user.LevelAccess,
+		true,
 	)
-	if serverError != nil {
-		return
-	}
+	return
+}
 
-	return userCreated, nil, nil
+func generatePassword() (string, error) {
+	buffer := make([]byte, 18)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
 }
 
 func (usecase *DBUserUsecase) CreateUser(name, username, password, passwordConfirmation, email string) (userCreated *models.User, usecaseError, serverError error) {
@@ -130,7 +149,7 @@ func (usecase *DBUserUsecase) CreateUser(name, username, password, passwordConfi
 		return
 	}
 
-	userCreated, serverError = usecase.userRepository.InsertUser(name, username, passwordHashed, email, user.LevelAccess)
+	userCreated, serverError = usecase.userRepository.InsertUser(name, username, passwordHashed, email, user.LevelAccess, false)
 	if serverError != nil {
 		return
 	}
@@ -158,7 +177,7 @@ func (usecase *DBUserUsecase) UpdateUser(id int64, name, username, password, ema
 	if serverError != nil {
 		return nil, serverError
 	}
-	if userFoundByEmail != nil && email != userFoundByEmail.Email {
+	if userFoundByEmail != nil && userFoundByEmail.ID != id {
 		return ErrUserAlreadyExistsWithEmail, nil
 	}
 
@@ -166,7 +185,7 @@ func (usecase *DBUserUsecase) UpdateUser(id int64, name, username, password, ema
 	if serverError != nil {
 		return nil, serverError
 	}
-	if userFoundByUsername != nil && username != userFoundByEmail.Username {
+	if userFoundByUsername != nil && userFoundByUsername.ID != id {
 		return ErrUserAlreadyExistsWithUsername, nil
 	}
 
@@ -190,9 +209,8 @@ func (usecase *DBUserUsecase) DeleteUser(id int64) (usecaseError, serverError er
 		return usecaseError, nil
 	}
 
-	usecase.userRepository.DeleteUser(id)
-
-	return nil, nil
+	serverError = usecase.userRepository.DeleteUser(id)
+	return nil, serverError
 }
 
 func (usecase *DBUserUsecase) GetUser(id int64) (userFound *models.User, usecaseError, serverError error) {
@@ -245,17 +263,61 @@ func (usecase *DBUserUsecase) ChangePassword(userId int64, oldPassword, newPassw
 		return nil, serverError
 	}
 
-	serverError = usecase.userRepository.UpdateUser(
-		userFound.ID,
-		userFound.Name,
-		userFound.Username,
-		passwordHashed,
-		userFound.Email,
-		userFound.LevelAccess,
-	)
+	serverError = usecase.userRepository.UpdatePassword(userFound.ID, passwordHashed, false)
+	if serverError != nil {
+		return
+	}
+	return
+}
+
+// ResetPassword is the administrative password change, without password a
+// random one is generated. The user must change it on the next login.
+func (usecase *DBUserUsecase) ResetPassword(userId int64, password string) (passwordGenerated string, usecaseError, serverError error) {
+	userFound, usecaseError, serverError := usecase.GetUser(userId)
+	if usecaseError != nil || serverError != nil {
+		return
+	}
+	if userFound == nil {
+		usecaseError = ErrUserNotFound
+		return
+	}
+
+	if password == "" {
+		passwordGenerated, serverError = generatePassword()
+		if serverError != nil {
+			return
+		}
+		password = passwordGenerated
+	}
+	if len(password) < 6 {
+		usecaseError = models.ErrPasswordIsSmall
+		return
+	}
+	if len(password) > 255 {
+		usecaseError = models.ErrPasswordIsLarge
+		return
+	}
+
+	passwordHashed, serverError := usecase.hashPassword.Hash(password)
 	if serverError != nil {
 		return
 	}
+
+	serverError = usecase.userRepository.UpdatePassword(userFound.ID, passwordHashed, true)
+	return
+}
+
+func (usecase *DBUserUsecase) DisableUser(id int64) (usecaseError, serverError error) {
+	userFound, usecaseError, serverError := usecase.GetUser(id)
+	if usecaseError != nil || serverError != nil {
+		return
+	}
+	if userFound == nil {
+		usecaseError = ErrUserNotFound
+		return
+	}
+
+	serverError = usecase.userRepository.UpdateDisabled(userFound.ID, true)
 	return
 }
 